@@ -0,0 +1,318 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// arffMissing is the marker of
+// missing values in ARFF files.
+const arffMissing = "?"
+
+// ReadARFF reads a dense ARFF file,
+// as described at http://www.cs.waikato.ac.nz/ml/weka/arff.html,
+// and loads it in memory as a SchemaTable.
+//
+// NUMERIC, REAL and INTEGER attributes are numeric columns,
+// nominal, STRING and DATE attributes are categorical columns.
+// Values of nominal attributes must be in their declared set.
+// As usual for ARFF files, last attribute is the label.
+// Missing values (?) are stored as Missing.
+//
+// Sparse ARFF data is not supported.
+func ReadARFF(r io.Reader) (SchemaTable, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+	s := bufio.NewScanner(dr)
+	var schema Schema
+	// nominal stores declared values
+	// of nominal attributes.
+	var nominal []map[string]struct{}
+	var data MemoryTable
+	inData := false
+	n := 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		if !inData {
+			keyword := strings.ToLower(strings.Fields(line)[0])
+			switch keyword {
+			case "@relation":
+			case "@attribute":
+				c, values, err := parseARFFAttribute(line)
+				if err != nil {
+					return nil, fmt.Errorf("learn: line %d: %v", n, err)
+				}
+				schema.Columns = append(schema.Columns, c)
+				nominal = append(nominal, values)
+			case "@data":
+				inData = true
+				if len(schema.Columns) == 0 {
+					return nil, errors.New("learn: no attributes in ARFF header")
+				}
+			default:
+				return nil, fmt.Errorf("learn: line %d: unknown ARFF declaration %q", n, keyword)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			return nil, fmt.Errorf("learn: line %d: sparse ARFF data is not supported", n)
+		}
+		fields, err := splitARFF(line)
+		if err != nil {
+			return nil, fmt.Errorf("learn: line %d: %v", n, err)
+		}
+		if len(fields) != len(schema.Columns) {
+			return nil, fmt.Errorf("learn: line %d has %d values, expected %d", n, len(fields), len(schema.Columns))
+		}
+		row := make([]interface{}, len(fields))
+		for j, e := range fields {
+			if e == arffMissing {
+				row[j] = Missing{}
+				continue
+			}
+			c := schema.Columns[j]
+			if c.Type == NumericColumn {
+				f, err := strconv.ParseFloat(e, 64)
+				if err != nil {
+					return nil, &ParseError{Row: n, Column: j + 1, Value: e, Type: NumericColumn}
+				}
+				row[j] = f
+				continue
+			}
+			if nominal[j] != nil {
+				if _, ok := nominal[j][e]; !ok {
+					return nil, fmt.Errorf("learn: line %d: %q is not a value of attribute %s", n, e, c.Name)
+				}
+			}
+			row[j] = e
+		}
+		data = append(data, row)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if !inData {
+		return nil, errors.New("learn: no @DATA section in ARFF file")
+	}
+	schema.Label = len(schema.Columns) - 1
+	return &schemaTable{
+		MemoryTable: data,
+		schema:      schema,
+	}, nil
+}
+
+// parseARFFAttribute parses an @ATTRIBUTE declaration
+// returning the set of declared values for nominal attributes.
+func parseARFFAttribute(line string) (Column, map[string]struct{}, error) {
+	// Strip "@attribute".
+	rest := strings.TrimSpace(line[len("@attribute"):])
+	names, err := splitARFFName(rest)
+	if err != nil {
+		return Column{}, nil, err
+	}
+	name, kind := names[0], strings.TrimSpace(names[1])
+	if strings.HasPrefix(kind, "{") {
+		if !strings.HasSuffix(kind, "}") {
+			return Column{}, nil, fmt.Errorf("unterminated nominal attribute %s", name)
+		}
+		values, err := splitARFF(kind[1 : len(kind)-1])
+		if err != nil {
+			return Column{}, nil, err
+		}
+		set := make(map[string]struct{}, len(values))
+		for _, v := range values {
+			set[v] = struct{}{}
+		}
+		return Column{Name: name, Type: CategoricalColumn}, set, nil
+	}
+	fields := strings.Fields(kind)
+	if len(fields) == 0 {
+		return Column{}, nil, fmt.Errorf("no type for attribute %s", name)
+	}
+	switch strings.ToLower(fields[0]) {
+	case "numeric", "real", "integer":
+		return Column{Name: name, Type: NumericColumn}, nil, nil
+	case "string", "date":
+		return Column{Name: name, Type: CategoricalColumn}, nil, nil
+	}
+	return Column{}, nil, fmt.Errorf("unsupported type %q of attribute %s", kind, name)
+}
+
+// splitARFFName splits the, possibly quoted,
+// name of an attribute from its type.
+func splitARFFName(s string) ([2]string, error) {
+	if s == "" {
+		return [2]string{}, errors.New("empty attribute")
+	}
+	if q := s[0]; q == '\'' || q == '"' {
+		end := strings.IndexByte(s[1:], q)
+		if end < 0 {
+			return [2]string{}, errors.New("unterminated quote in attribute name")
+		}
+		return [2]string{s[1 : end+1], s[end+2:]}, nil
+	}
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return [2]string{}, fmt.Errorf("no type for attribute %s", s)
+	}
+	return [2]string{s[:i], s[i+1:]}, nil
+}
+
+// splitARFF splits comma separated values
+// that can be quoted with single or double quotes.
+func splitARFF(line string) ([]string, error) {
+	var fields []string
+	var field bytes.Buffer
+	var quote rune
+	quoted := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			field.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			quoted = true
+		case r == ',':
+			fields = append(fields, arffField(field.String(), quoted))
+			field.Reset()
+			quoted = false
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	return append(fields, arffField(field.String(), quoted)), nil
+}
+
+// arffField trims unquoted values.
+func arffField(s string, quoted bool) string {
+	if quoted {
+		return s
+	}
+	return strings.TrimSpace(s)
+}
+
+// WriteARFF writes data to w in ARFF format
+// with the given relation name.
+//
+// If data is a SchemaTable its names and types are used,
+// otherwise columns are named c1, c2, ... cn and their types
+// are taken from their first non missing value.
+// Categorical columns are declared as nominal attributes
+// with the set of their values.
+func WriteARFF(w io.Writer, data Table, relation string) error {
+	nRows, nColumns := data.Caps()
+	var schema Schema
+	schema.Columns = make([]Column, nColumns)
+	if st, ok := data.(SchemaTable); ok {
+		copy(schema.Columns, st.Schema().Columns)
+	} else {
+		for j := range schema.Columns {
+			schema.Columns[j].Name = "c" + strconv.Itoa(j+1)
+		}
+	}
+	nominal := make([]map[string]struct{}, nColumns)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		if len(row) != nColumns {
+			return rowWidthErr(i, len(row), nColumns)
+		}
+		for j, e := range row {
+			if IsMissing(e) {
+				continue
+			}
+			if schema.Columns[j].Type == AutoColumn {
+				schema.Columns[j].Type = CategoricalColumn
+				if _, ok := e.(float64); ok {
+					schema.Columns[j].Type = NumericColumn
+				}
+			}
+			if schema.Columns[j].Type != CategoricalColumn {
+				continue
+			}
+			s, err := formatValue(e, arffMissing)
+			if err != nil {
+				return err
+			}
+			if nominal[j] == nil {
+				nominal[j] = make(map[string]struct{})
+			}
+			nominal[j][s] = struct{}{}
+		}
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "@RELATION %s\n\n", quoteARFF(relation))
+	for j, c := range schema.Columns {
+		kind := "NUMERIC"
+		if c.Type == CategoricalColumn {
+			values := orderMapSet(nominal[j])
+			for i, v := range values {
+				values[i] = quoteARFF(v)
+			}
+			kind = "{" + strings.Join(values, ",") + "}"
+		}
+		fmt.Fprintf(bw, "@ATTRIBUTE %s %s\n", quoteARFF(c.Name), kind)
+	}
+	fmt.Fprint(bw, "\n@DATA\n")
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		if len(row) != nColumns {
+			return rowWidthErr(i, len(row), nColumns)
+		}
+		for j, e := range row {
+			s, err := formatValue(e, arffMissing)
+			if err != nil {
+				return err
+			}
+			if !IsMissing(e) {
+				s = quoteARFF(s)
+			}
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(s)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// quoteARFF quotes s if it contains
+// special characters, with double quotes
+// if s contains single ones.
+func quoteARFF(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t,'\"%{}?") {
+		return s
+	}
+	q := "'"
+	if strings.Contains(s, q) {
+		q = `"`
+	}
+	return q + s + q
+}
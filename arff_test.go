@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const irisARFF = `% Iris subset.
+@RELATION iris
+
+@ATTRIBUTE sepallength REAL
+@ATTRIBUTE 'petal width' NUMERIC
+@ATTRIBUTE class {Iris-setosa,'Iris virginica'}
+
+@DATA
+5.1,0.2,Iris-setosa
+?,2.3,'Iris virginica'
+`
+
+func TestReadARFF(t *testing.T) {
+	data, err := ReadARFF(strings.NewReader(irisARFF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Schema{
+		Columns: []Column{
+			{"sepallength", NumericColumn},
+			{"petal width", NumericColumn},
+			{"class", CategoricalColumn},
+		},
+		Label: 2,
+	}
+	if !reflect.DeepEqual(data.Schema(), expected) {
+		t.Fatalf("expected schema %v, got %v", expected, data.Schema())
+	}
+	row, err := data.Row(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsMissing(row[0]) || row[1] != 2.3 || row[2] != "Iris virginica" {
+		t.Fatalf("unexpected row: %v", row)
+	}
+	_, err = ReadARFF(strings.NewReader(strings.Replace(irisARFF, "Iris-setosa\n", "Iris-versicolor\n", 1)))
+	if err == nil {
+		t.Fatal("expected an error for undeclared nominal value")
+	}
+	for _, attribute := range []string{"@ATTRIBUTE 'x'", "@ATTRIBUTE 'x' \t"} {
+		_, err = ReadARFF(strings.NewReader(strings.Replace(irisARFF, "@ATTRIBUTE sepallength REAL", attribute, 1)))
+		if err == nil || !strings.Contains(err.Error(), "no type for attribute x") {
+			t.Errorf("%q: unexpected error %v", attribute, err)
+		}
+	}
+}
+
+func TestWriteARFF(t *testing.T) {
+	data, err := ReadARFF(strings.NewReader(irisARFF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteARFF(&buf, data, "iris"); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadARFF(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, data) {
+		t.Fatalf("expected %v, got %v", data, read)
+	}
+	for _, ragged := range []MemoryTable{{{1.0, "a"}, {2.0}}, {{1.0, "a"}, {2.0, "b", "c"}}} {
+		if err := WriteARFF(&buf, ragged, "ragged"); err == nil {
+			t.Errorf("expected an error writing %v", ragged)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// BinStrategy selects how a Binner
+// chooses the edges of bins.
+type BinStrategy uint8
+
+const (
+	BinEqualWidth     BinStrategy = iota // Bins of the same width between min and max.
+	BinEqualFrequency                    // Bins with about the same number of values.
+	BinMDLP                              // Supervised, Fayyad and Irani's MDLP using the label.
+)
+
+// defaultBins is the number of bins
+// used by Binner if not specified.
+const defaultBins = 5
+
+// Binner is a Step that discretizes numeric columns,
+// replacing values with the Category of their bin.
+// Bins are labelled as intervals like "[1.5, 3)"
+// and their Vocabulary is in ascending order,
+// so a Category's Index is the one of its bin.
+//
+// Values outside training range fall in the first
+// or last bin, Missing values are left in place.
+type Binner struct {
+	Strategy BinStrategy
+	// Bins is the number of bins of unsupervised
+	// strategies, 5 if <= 0. MDLP chooses it from data.
+	Bins int
+	// Columns to discretize, if nil all numeric
+	// columns but the label are discretized.
+	Columns      []int
+	columns      []int // Discretized columns, set by Fit.
+	cuts         map[int][]float64
+	vocabularies map[int]Vocabulary
+}
+
+// Fit computes the edges of bins
+// of data's numeric columns.
+func (b *Binner) Fit(data Table) error {
+	nRows, _ := data.Caps()
+	label := NewFrame(data).Schema().Label
+	columns := b.Columns
+	if columns == nil {
+		var err error
+		if columns, err = numericCandidates(data); err != nil {
+			return err
+		}
+	}
+	if b.Strategy == BinMDLP && label < 0 {
+		return errors.New("learn: MDLP binning needs a label column")
+	}
+	samples := make(map[int][]labeled, len(columns))
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		for _, j := range columns {
+			if j >= len(row) {
+				return shortRowErr(i, j)
+			}
+			v, ok := row[j].(float64)
+			if !ok {
+				if IsMissing(row[j]) {
+					continue
+				}
+				return unknownTypeErr(row[j])
+			}
+			s := labeled{v: v}
+			if b.Strategy == BinMDLP {
+				if label >= len(row) {
+					return shortRowErr(i, label)
+				}
+				if IsMissing(row[label]) {
+					continue
+				}
+				if s.l, ok = valueKey(row[label]); !ok {
+					return unknownTypeErr(row[label])
+				}
+			}
+			samples[j] = append(samples[j], s)
+		}
+	}
+	bins := b.Bins
+	if bins <= 0 {
+		bins = defaultBins
+	}
+	b.cuts = make(map[int][]float64, len(columns))
+	b.vocabularies = make(map[int]Vocabulary, len(columns))
+	for _, j := range columns {
+		s := samples[j]
+		sort.Slice(s, func(a, c int) bool { return s[a].v < s[c].v })
+		var cuts []float64
+		switch b.Strategy {
+		case BinEqualWidth:
+			cuts = equalWidthCuts(s, bins)
+		case BinEqualFrequency:
+			cuts = equalFrequencyCuts(s, bins)
+		case BinMDLP:
+			cuts = mdlpCuts(s)
+		default:
+			return errors.New("learn: unknown binning strategy")
+		}
+		b.cuts[j] = cuts
+		b.vocabularies[j] = binLabels(cuts)
+	}
+	b.columns = columns
+	return nil
+}
+
+// labeled is a value with the label of its row.
+type labeled struct {
+	v float64
+	l string
+}
+
+func equalWidthCuts(sorted []labeled, bins int) []float64 {
+	if len(sorted) == 0 {
+		return nil
+	}
+	min, max := sorted[0].v, sorted[len(sorted)-1].v
+	if min == max {
+		return nil
+	}
+	cuts := make([]float64, 0, bins-1)
+	w := (max - min) / float64(bins)
+	for i := 1; i < bins; i++ {
+		cuts = append(cuts, min+w*float64(i))
+	}
+	return cuts
+}
+
+func equalFrequencyCuts(sorted []labeled, bins int) []float64 {
+	values := make([]float64, len(sorted))
+	for i, s := range sorted {
+		values[i] = s.v
+	}
+	var cuts []float64
+	for i := 1; i < bins && len(values) > 0; i++ {
+		c := quantile(values, float64(i)/float64(bins))
+		// Many equal values give
+		// equal cuts, keep one.
+		if c > values[0] && (len(cuts) == 0 || c > cuts[len(cuts)-1]) {
+			cuts = append(cuts, c)
+		}
+	}
+	return cuts
+}
+
+// mdlpCuts recursively splits sorted samples where class entropy
+// is minimum while the split is accepted by the
+// minimum description length principle.
+func mdlpCuts(sorted []labeled) []float64 {
+	n := len(sorted)
+	if n < 2 {
+		return nil
+	}
+	right := make(map[string]float64)
+	for _, s := range sorted {
+		right[s.l]++
+	}
+	entS := entropy(right, float64(n))
+	left := make(map[string]float64)
+	best := -1
+	bestE := math.Inf(1)
+	for i := 1; i < n; i++ {
+		l := sorted[i-1].l
+		left[l]++
+		right[l]--
+		if right[l] == 0 {
+			delete(right, l)
+		}
+		if sorted[i].v == sorted[i-1].v {
+			continue
+		}
+		e := (float64(i)*entropy(left, float64(i)) + float64(n-i)*entropy(right, float64(n-i))) / float64(n)
+		if e < bestE {
+			best, bestE = i, e
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	counts := func(s []labeled) map[string]float64 {
+		c := make(map[string]float64)
+		for _, e := range s {
+			c[e.l]++
+		}
+		return c
+	}
+	c1, c2 := counts(sorted[:best]), counts(sorted[best:])
+	e1, e2 := entropy(c1, float64(best)), entropy(c2, float64(n-best))
+	k, k1, k2 := float64(len(counts(sorted))), float64(len(c1)), float64(len(c2))
+	gain := entS - bestE
+	delta := math.Log2(math.Pow(3, k)-2) - (k*entS - k1*e1 - k2*e2)
+	if gain <= (math.Log2(float64(n-1))+delta)/float64(n) {
+		return nil
+	}
+	cut := (sorted[best-1].v + sorted[best].v) / 2
+	cuts := append(mdlpCuts(sorted[:best]), cut)
+	return append(cuts, mdlpCuts(sorted[best:])...)
+}
+
+// entropy returns, in bits, the entropy
+// of labels with counts summing to n.
+func entropy(counts map[string]float64, n float64) float64 {
+	var e float64
+	for _, c := range counts {
+		if c > 0 {
+			p := c / n
+			e -= p * math.Log2(p)
+		}
+	}
+	return e
+}
+
+// binLabels returns labels of the
+// intervals delimited by cuts.
+func binLabels(cuts []float64) Vocabulary {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	if len(cuts) == 0 {
+		return Vocabulary{"(-inf, +inf)"}
+	}
+	labels := make(Vocabulary, 0, len(cuts)+1)
+	labels = append(labels, "(-inf, "+format(cuts[0])+")")
+	for i := 1; i < len(cuts); i++ {
+		labels = append(labels, "["+format(cuts[i-1])+", "+format(cuts[i])+")")
+	}
+	return append(labels, "["+format(cuts[len(cuts)-1])+", +inf)")
+}
+
+// Cuts returns the inner edges of bins
+// of each discretized column.
+func (b *Binner) Cuts() map[int][]float64 {
+	return b.cuts
+}
+
+// Transform uses Table's Update() to replace
+// values of discretized columns with the Category
+// of their bin and returns data, see Step.
+func (b *Binner) Transform(data Table) (Table, error) {
+	if b.cuts == nil {
+		return nil, errors.New("learn: Binner is not fitted")
+	}
+	nRows, _ := data.Caps()
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range b.columns {
+			if j >= len(row) {
+				continue
+			}
+			v, ok := row[j].(float64)
+			if !ok {
+				continue
+			}
+			cuts := b.cuts[j]
+			bin := sort.Search(len(cuts), func(k int) bool { return cuts[k] > v })
+			vocabulary := b.vocabularies[j]
+			row[j] = NewCategory(vocabulary[bin], vocabulary)
+		}
+		if err := data.Update(i, row); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
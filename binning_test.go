@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+var binningData = MemoryTable{
+	{0.0, "a"},
+	{1.0, "a"},
+	{2.0, "a"},
+	{3.0, "a"},
+	{Missing{}, "a"},
+	{7.0, "b"},
+	{8.0, "b"},
+	{9.0, "b"},
+	{10.0, "b"},
+}
+
+func TestBinner(t *testing.T) {
+	cases := []struct {
+		strategy BinStrategy
+		bins     int
+		cuts     []float64
+	}{
+		{BinEqualWidth, 2, []float64{5}},
+		{BinEqualFrequency, 4, []float64{1.75, 5, 8.25}},
+		{BinMDLP, 0, []float64{5}},
+	}
+	for _, c := range cases {
+		data := cloneTable(binningData)
+		b := &Binner{Strategy: c.strategy, Bins: c.bins}
+		if err := b.Fit(data); err != nil {
+			t.Fatal(err)
+		}
+		if cuts := b.Cuts()[0]; !reflect.DeepEqual(cuts, c.cuts) {
+			t.Errorf("strategy %d: expected cuts %v, got %v", c.strategy, c.cuts, cuts)
+		}
+		if _, err := b.Transform(data); err != nil {
+			t.Fatal(err)
+		}
+		last := data[len(data)-1][0].(*Category)
+		if last.Index() != len(c.cuts) {
+			t.Errorf("strategy %d: last value in bin %d", c.strategy, last.Index())
+		}
+		if !IsMissing(data[4][0]) {
+			t.Errorf("strategy %d: missing value binned", c.strategy)
+		}
+	}
+}
+
+func TestBinner_test(t *testing.T) {
+	b := &Binner{Strategy: BinEqualWidth, Bins: 2}
+	if err := b.Fit(binningData); err != nil {
+		t.Fatal(err)
+	}
+	test := MemoryTable{{-4.0}, {5.0}, {42.0}}
+	if _, err := b.Transform(test); err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, row := range test {
+		labels = append(labels, row[0].(*Category).Label())
+	}
+	if expected := []string{"(-inf, 5)", "[5, +inf)", "[5, +inf)"}; !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected %v, got %v", expected, labels)
+	}
+}
+
+func TestBinner_refit(t *testing.T) {
+	b := &Binner{Strategy: BinMDLP}
+	if err := b.Fit(MemoryTable{{1.0, 2.0, "a"}, {5.0, 6.0, "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Columns != nil {
+		t.Errorf("Columns set by Fit: %v", b.Columns)
+	}
+	if err := b.Fit(binningData); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Cuts()[1]; ok {
+		t.Error("label column discretized")
+	}
+	// Row without label.
+	err := b.Fit(MemoryTable{{1.0, "a"}, {5.0}})
+	if err == nil || err.Error() != "learn: row 1 has no column 1" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestBinner_missing(t *testing.T) {
+	data := cloneTable(binningData)
+	data[0][0], data[4][0] = Missing{}, 4.0
+	b := &Binner{Strategy: BinEqualWidth, Bins: 2}
+	if err := b.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if cuts := b.Cuts()[0]; !reflect.DeepEqual(cuts, []float64{5.5}) {
+		t.Errorf("expected cuts [5.5], got %v", cuts)
+	}
+}
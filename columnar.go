@@ -0,0 +1,390 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// columnarMagic starts files
+// written by WriteColumnar.
+const columnarMagic = "LCOL\x01"
+
+// Kinds of values stored
+// in a column of columnar files.
+const (
+	floatValues byte = iota + 1
+	stringValues
+	categoryValues
+)
+
+// ErrBadFormat is returned reading
+// malformed columnar data.
+var ErrBadFormat = errors.New("learn: bad columnar format")
+
+// WriteColumnar writes data to w in a compact
+// binary format, stored column by column, that can be
+// read back quickly with ReadColumnar.
+// It is meant to pass Tables, also already normalized ones,
+// between stages of a pipeline.
+//
+// All rows must have the same number of values and
+// all the values of a column must have the same type
+// or be Missing. Names and label are taken from Schema
+// if data is a SchemaTable.
+//
+// The format is:
+//
+//	magic "LCOL" and version
+//	columns, rows and label+1 as uvarints
+//	for each column: name and kind of values
+//	for each column: its values
+//
+// Numbers are stored as IEEE 754 little endian
+// preceded by a bitmap of missing values.
+// Strings and categories are stored as a dictionary
+// followed by one code per row, where 0 is a missing value.
+func WriteColumnar(w io.Writer, data Table) error {
+	nRows, nColumns := data.Caps()
+	schema := NewFrame(data).Schema()
+	// Columns are transposed in memory
+	// to write them one after the other.
+	columns := make([][]interface{}, nColumns)
+	kinds := make([]byte, nColumns)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		if len(row) != nColumns {
+			return rowWidthErr(i, len(row), nColumns)
+		}
+		for j, e := range row {
+			var k byte
+			switch e.(type) {
+			case float64:
+				k = floatValues
+			case string:
+				k = stringValues
+			case *Category:
+				k = categoryValues
+			case Missing:
+			default:
+				return unknownTypeErr(e)
+			}
+			if k != 0 && kinds[j] != 0 && kinds[j] != k {
+				return fmt.Errorf("learn: mixed types in column %d", j)
+			}
+			if k != 0 {
+				kinds[j] = k
+			}
+			columns[j] = append(columns[j], e)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	enc := &columnarWriter{w: bw}
+	enc.bytes([]byte(columnarMagic))
+	enc.uvarint(uint64(nColumns))
+	enc.uvarint(uint64(nRows))
+	enc.uvarint(uint64(schema.Label + 1))
+	for j, c := range schema.Columns {
+		if kinds[j] == 0 {
+			// Only missing values.
+			kinds[j] = floatValues
+		}
+		enc.string(c.Name)
+		enc.bytes([]byte{kinds[j]})
+	}
+	for j, values := range columns {
+		switch kinds[j] {
+		case floatValues:
+			enc.floats(values)
+		default:
+			enc.codes(values)
+		}
+	}
+	if enc.err != nil {
+		return enc.err
+	}
+	return bw.Flush()
+}
+
+type columnarWriter struct {
+	w   *bufio.Writer
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (e *columnarWriter) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *columnarWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(e.buf[:], v)
+	e.bytes(e.buf[:n])
+}
+
+func (e *columnarWriter) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.bytes([]byte(s))
+}
+
+// floats writes the bitmap of missing values
+// and then the values.
+func (e *columnarWriter) floats(values []interface{}) {
+	bitmap := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if IsMissing(v) {
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+	e.bytes(bitmap)
+	var b [8]byte
+	for _, v := range values {
+		f, _ := v.(float64)
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+		e.bytes(b[:])
+	}
+}
+
+// codes writes the dictionary of strings, or categories,
+// and then the code of each value.
+func (e *columnarWriter) codes(values []interface{}) {
+	type entry struct {
+		label           string
+		data, catNumber uint
+	}
+	index := make(map[entry]uint64)
+	var dict []entry
+	codes := make([]uint64, len(values))
+	for i, v := range values {
+		var k entry
+		switch c := v.(type) {
+		case string:
+			k.label = c
+		case *Category:
+			k = entry{c.label, c.data, c.catNumber}
+		default:
+			// Missing.
+			continue
+		}
+		code, ok := index[k]
+		if !ok {
+			dict = append(dict, k)
+			code = uint64(len(dict))
+			index[k] = code
+		}
+		codes[i] = code
+	}
+	e.uvarint(uint64(len(dict)))
+	for _, k := range dict {
+		e.string(k.label)
+		e.uvarint(uint64(k.data))
+		e.uvarint(uint64(k.catNumber))
+	}
+	for _, c := range codes {
+		e.uvarint(c)
+	}
+}
+
+// maxColumnarLen limits counts and lengths
+// read from columnar data.
+const maxColumnarLen = 1 << 30
+
+// ReadColumnar reads a Table written by WriteColumnar
+// and loads it in memory.
+// ErrBadFormat is returned for malformed
+// or truncated data.
+func ReadColumnar(r io.Reader) (SchemaTable, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(columnarMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != columnarMagic {
+		return nil, ErrBadFormat
+	}
+	// Data is read whole, so that counts
+	// can be checked against its length
+	// before allocating memory for them.
+	rest, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	dec := &columnarReader{r: bytes.NewReader(rest)}
+	// Each column has at least a name
+	// length and a kind.
+	nColumns := dec.count(2)
+	nRows := dec.count(0)
+	label := int(dec.uvarint()) - 1
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	if label < -1 || label >= nColumns {
+		return nil, ErrBadFormat
+	}
+	schema := Schema{
+		Columns: make([]Column, nColumns),
+		Label:   label,
+	}
+	kinds := make([]byte, nColumns)
+	for j := range schema.Columns {
+		schema.Columns[j].Name = dec.string()
+		kinds[j] = dec.byte()
+		schema.Columns[j].Type = CategoricalColumn
+		if kinds[j] == floatValues {
+			schema.Columns[j].Type = NumericColumn
+		}
+	}
+	if dec.err != nil {
+		return nil, dec.err
+	}
+	// Each value takes at least a byte.
+	if nColumns > 0 && nRows > dec.r.Len()/nColumns {
+		return nil, ErrBadFormat
+	}
+	data := make(MemoryTable, nRows)
+	for i := range data {
+		data[i] = make([]interface{}, nColumns)
+	}
+	for j := 0; j < nColumns; j++ {
+		switch kinds[j] {
+		case floatValues:
+			dec.floats(data, j)
+		case stringValues, categoryValues:
+			dec.codes(data, j, kinds[j] == categoryValues)
+		default:
+			return nil, ErrBadFormat
+		}
+		if dec.err != nil {
+			return nil, dec.err
+		}
+	}
+	return &schemaTable{
+		MemoryTable: data,
+		schema:      schema,
+	}, nil
+}
+
+type columnarReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+// fail records the first error,
+// reading past the end of data
+// means that it is truncated.
+func (d *columnarReader) fail(err error) {
+	if d.err != nil {
+		return
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = ErrBadFormat
+	}
+	d.err = err
+}
+
+func (d *columnarReader) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.fail(err)
+	return v
+}
+
+// count reads a count of items taking at least
+// size bytes each, failing if remaining data
+// is too short to store them.
+func (d *columnarReader) count(size int) int {
+	v := d.uvarint()
+	if v > maxColumnarLen || (size > 0 && v > uint64(d.r.Len()/size)) {
+		d.fail(ErrBadFormat)
+		return 0
+	}
+	return int(v)
+}
+
+func (d *columnarReader) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	d.fail(err)
+	return b
+}
+
+func (d *columnarReader) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > d.r.Len() {
+		d.fail(ErrBadFormat)
+		return nil
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.r, b)
+	d.fail(err)
+	return b
+}
+
+func (d *columnarReader) string() string {
+	return string(d.read(d.count(1)))
+}
+
+// floats reads j-th column of numbers.
+func (d *columnarReader) floats(data MemoryTable, j int) {
+	bitmap := d.read((len(data) + 7) / 8)
+	for i := range data {
+		b := d.read(8)
+		if d.err != nil {
+			return
+		}
+		if bitmap[i/8]&(1<<uint(i%8)) != 0 {
+			data[i][j] = Missing{}
+			continue
+		}
+		data[i][j] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+}
+
+// codes reads j-th column of strings,
+// or categories.
+func (d *columnarReader) codes(data MemoryTable, j int, categories bool) {
+	// Each entry has at least a label
+	// length, an index and a size.
+	n := d.count(3)
+	dict := make([]Category, n)
+	for k := range dict {
+		dict[k].label = d.string()
+		dict[k].data = uint(d.uvarint())
+		dict[k].catNumber = uint(d.uvarint())
+	}
+	for i := range data {
+		code := d.uvarint()
+		if d.err != nil {
+			return
+		}
+		switch {
+		case code == 0:
+			data[i][j] = Missing{}
+		case code > uint64(n):
+			d.fail(ErrBadFormat)
+			return
+		case categories:
+			// Each row has its own category.
+			c := dict[code-1]
+			data[i][j] = &c
+		default:
+			data[i][j] = dict[code-1].label
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnar(t *testing.T) {
+	data, err := ReadCSV("datasets/iris.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = Normalize(data, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := data.Row(1)
+	row[0] = Missing{}
+	var buf bytes.Buffer
+	if err := WriteColumnar(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadColumnar(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, data) {
+		t.Fatal("Table read differs from the written one")
+	}
+	// Truncated data.
+	buf.Reset()
+	WriteColumnar(&buf, data)
+	if _, err := ReadColumnar(bytes.NewReader(buf.Bytes()[:buf.Len()/2])); err != ErrBadFormat {
+		t.Fatalf("expected ErrBadFormat for truncated data, got %v", err)
+	}
+	for _, ragged := range []MemoryTable{{{1.0, "a"}, {2.0}}, {{1.0, "a"}, {2.0, "b", "c"}}} {
+		if err := WriteColumnar(&buf, ragged); err == nil {
+			t.Errorf("expected an error writing %v", ragged)
+		}
+	}
+}
+
+func TestReadColumnar_corrupt(t *testing.T) {
+	huge := "\xff\xff\xff\xff\xff\xff\xff\xff\x7f"
+	cases := []string{
+		// Columns, rows and label.
+		huge + "\x01\x00",
+		"\x01" + huge + "\x00",
+		"\x01\x01\x09",
+		// Name length.
+		"\x01\x01\x00" + huge,
+		// Dictionary size.
+		"\x01\x01\x00\x01x\x02" + huge,
+		// Code out of dictionary.
+		"\x01\x01\x00\x01x\x02\x00\xff\xff\xff\xff\xff\xff\xff\xff\x01",
+	}
+	for i, c := range cases {
+		_, err := ReadColumnar(strings.NewReader(columnarMagic + c))
+		if err != ErrBadFormat {
+			t.Errorf("case %d: expected ErrBadFormat, got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// defaultTopK is the number of most frequent
+// labels reported by Describe if not specified.
+const defaultTopK = 5
+
+// quantileSamples is the maximum number of values
+// of a column kept by Describe to compute quantiles.
+const quantileSamples = 10000
+
+// Description is an overview
+// of a Table's columns.
+type Description struct {
+	Rows    int
+	Columns []ColumnDescription
+}
+
+// ColumnDescription summarizes a Table's column.
+// Mean, StdDev and Quantiles are set for numeric
+// columns only, Top for categorical ones.
+// Quantiles, but Min and Max, are estimated on
+// a random sample of 10000 values for longer columns.
+type ColumnDescription struct {
+	Name         string
+	Type         ColumnType
+	MissingRatio float64 // Fraction of Missing values.
+	Distinct     int     // Number of distinct values.
+	Mean         float64
+	StdDev       float64
+	Quantiles    Quantiles
+	Top          []Frequency // Most frequent labels.
+}
+
+// Frequency stores the number
+// of occurrences of a label.
+type Frequency struct {
+	Label string
+	Count int
+}
+
+// Describe reads data once, keeping a bounded sample
+// of numeric values, and returns a Description
+// of its columns, with the k most frequent labels
+// of categorical columns, 5 if k <= 0.
+// Names and types are taken from Schema if data
+// is a SchemaTable.
+func Describe(data Table, k int) (Description, error) {
+	if k <= 0 {
+		k = defaultTopK
+	}
+	nRows, nColumns := data.Caps()
+	stats := make([]*ColumnStats, nColumns)
+	// numbers are reservoir samples
+	// of numeric values, seeded to get
+	// the same Description every time.
+	numbers := make([][]float64, nColumns)
+	rnd := rand.New(rand.NewSource(1))
+	for j := range stats {
+		stats[j] = &ColumnStats{}
+	}
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return Description{}, err
+		}
+		for j, e := range row {
+			if j >= nColumns {
+				break
+			}
+			if err := stats[j].Add(e); err != nil {
+				return Description{}, err
+			}
+			v, ok := e.(float64)
+			switch {
+			case !ok:
+			case len(numbers[j]) < quantileSamples:
+				numbers[j] = append(numbers[j], v)
+			default:
+				// v replaces a sample with probability
+				// quantileSamples / values seen so far.
+				if slot := rnd.Intn(stats[j].numbers); slot < quantileSamples {
+					numbers[j][slot] = v
+				}
+			}
+		}
+	}
+	schema := NewFrame(data).Schema()
+	d := Description{
+		Rows:    nRows,
+		Columns: make([]ColumnDescription, nColumns),
+	}
+	for j, s := range stats {
+		c := &d.Columns[j]
+		c.Name = schema.Columns[j].Name
+		c.Type = schema.Columns[j].Type
+		if c.Type == AutoColumn {
+			c.Type = NumericColumn
+			if s.Categorical() {
+				c.Type = CategoricalColumn
+			}
+		}
+		if nRows > 0 {
+			c.MissingRatio = float64(s.Missing) / float64(nRows)
+		}
+		c.Distinct = s.Distinct()
+		if c.Type == CategoricalColumn {
+			c.Mean, c.StdDev = math.NaN(), math.NaN()
+			c.Top = s.top(k)
+			continue
+		}
+		c.Mean, c.StdDev = s.Mean(), s.StdDev()
+		if len(numbers[j]) == 0 {
+			nan := math.NaN()
+			c.Quantiles = Quantiles{nan, nan, nan, nan, nan}
+			continue
+		}
+		sort.Float64s(numbers[j])
+		c.Quantiles = fiveNumbers(numbers[j])
+		c.Quantiles.Min, c.Quantiles.Max = s.Min, s.Max
+	}
+	return d, nil
+}
+
+// top returns the k most frequent labels,
+// ties are sorted by label.
+func (s *ColumnStats) top(k int) []Frequency {
+	var f []Frequency
+	for l, c := range s.counts {
+		f = append(f, Frequency{l, c})
+	}
+	sort.Slice(f, func(i, j int) bool {
+		if f[i].Count != f[j].Count {
+			return f[i].Count > f[j].Count
+		}
+		return f[i].Label < f[j].Label
+	})
+	if len(f) > k {
+		f = f[:k]
+	}
+	return f
+}
+
+func (d Description) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%12s | %-12s | %8s | %8s | %12s | %12s | %12s | %12s | %12s |\n",
+		"column", "type", "missing", "distinct", "mean", "std", "min", "median", "max")
+	for _, c := range d.Columns {
+		if c.Type == CategoricalColumn {
+			fmt.Fprintf(&buf, "%12s | %-12s | %8.2f | %8d | %12s | %12s | %12s | %12s | %12s |\n",
+				c.Name, c.Type, c.MissingRatio, c.Distinct, "-", "-", "-", "-", "-")
+			continue
+		}
+		fmt.Fprintf(&buf, "%12s | %-12s | %8.2f | %8d | %12.4f | %12.4f | %12.4f | %12.4f | %12.4f |\n",
+			c.Name, c.Type, c.MissingRatio, c.Distinct, c.Mean, c.StdDev,
+			c.Quantiles.Min, c.Quantiles.Median, c.Quantiles.Max)
+	}
+	fmt.Fprintf(&buf, "Rows: %d", d.Rows)
+	for _, c := range d.Columns {
+		if len(c.Top) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n%12s |", c.Name)
+		for _, f := range c.Top {
+			fmt.Fprintf(&buf, " %s (%d)", f.Label, f.Count)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	data := MemoryTable{
+		{1.0, "a"},
+		{2.0, "b"},
+		{Missing{}, "a"},
+		{5.0, "c"},
+		{2.0, "a"},
+	}
+	d, err := Describe(data, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := d.Columns[0]
+	if x.Name != "c1" || x.Type != NumericColumn || x.MissingRatio != 0.2 || x.Distinct != 3 {
+		t.Errorf("wrong numeric description: %+v", x)
+	}
+	if !floatsAreEqual(x.Mean, 2.5) || x.Quantiles.Median != 2 || x.Quantiles.Max != 5 {
+		t.Errorf("wrong numeric stats: %+v", x)
+	}
+	c := d.Columns[1]
+	if c.Type != CategoricalColumn || c.Distinct != 3 {
+		t.Errorf("wrong categorical description: %+v", c)
+	}
+	if expected := []Frequency{{"a", 3}, {"b", 1}}; !reflect.DeepEqual(c.Top, expected) {
+		t.Errorf("expected top %v, got %v", expected, c.Top)
+	}
+	s := d.String()
+	if !strings.Contains(s, "Rows: 5") || !strings.Contains(s, "c2 | a (3) b (1)") {
+		t.Errorf("unexpected report:\n%s", s)
+	}
+	lines := strings.Split(s, "\n")
+	for _, l := range lines[1:3] {
+		if strings.Index(l, "|") != strings.Index(lines[0], "|") || len(l) != len(lines[0]) {
+			t.Errorf("row not aligned with header:\n%s\n%s", lines[0], l)
+		}
+	}
+}
+
+func TestDescribe_sample(t *testing.T) {
+	n := 3 * quantileSamples
+	data := make(MemoryTable, n)
+	for i := range data {
+		data[i] = []interface{}{float64(i)}
+	}
+	d, err := Describe(data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := d.Columns[0].Quantiles
+	if q.Min != 0 || q.Max != float64(n-1) {
+		t.Errorf("wrong min or max: %+v", q)
+	}
+	if math.Abs(q.Median-float64(n)/2) > 0.05*float64(n) {
+		t.Errorf("median %v too far from %v", q.Median, n/2)
+	}
+}
@@ -9,16 +9,6 @@ import (
 	"math"
 )
 
-func hammingD(a, b uint) uint {
-	var dist uint
-	val := a ^ b
-	for val != 0 {
-		dist++
-		val &= val - 1
-	}
-	return dist
-}
-
 func manhattan(a, b float64) float64 {
 	d := a - b
 	if d < 0 {
@@ -34,7 +24,12 @@ func euclidean(a, b float64) float64 {
 
 // elementsDistance returns distance for two elements of same type
 // (quantitative or categorical).
+// If any of the elements is Missing maximum distance
+// of categorical features, 1, is returned.
 func elementsDistance(a1, a2 interface{}) (d float64, er error) {
+	if IsMissing(a1) || IsMissing(a2) {
+		return 1, nil
+	}
 	switch v1 := a1.(type) {
 	case float64:
 		v2, ok := a2.(float64)
@@ -43,8 +38,8 @@ func elementsDistance(a1, a2 interface{}) (d float64, er error) {
 		}
 		return manhattan(v1, v2), nil
 		//return euclidean(v1, v2), nil
-	case *category:
-		v2, ok := a2.(*category)
+	case *Category:
+		v2, ok := a2.(*Category)
 		if !ok {
 			return -1, typeMismatchErr(a1, a2)
 		}
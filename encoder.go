@@ -0,0 +1,465 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// categoryLabel returns the label
+// of a categorical value.
+func categoryLabel(e interface{}) (string, bool) {
+	switch v := e.(type) {
+	case string:
+		return v, true
+	case *Category:
+		return v.label, true
+	}
+	return "", false
+}
+
+// categoricalColumns returns vocabularies of columns in data.
+// If columns is nil they are the ones with categorical
+// values, label excluded.
+func categoricalColumns(data Table, columns []int) ([]int, map[int]Vocabulary, error) {
+	nRows, nColumns := data.Caps()
+	label := NewFrame(data).Schema().Label
+	sets := make([]map[string]struct{}, nColumns)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j, e := range row {
+			if j >= nColumns {
+				break
+			}
+			l, ok := categoryLabel(e)
+			if !ok {
+				continue
+			}
+			if sets[j] == nil {
+				sets[j] = make(map[string]struct{})
+			}
+			sets[j][l] = struct{}{}
+		}
+	}
+	if columns == nil {
+		for j, set := range sets {
+			if set != nil && j != label {
+				columns = append(columns, j)
+			}
+		}
+	}
+	vocabularies := make(map[int]Vocabulary, len(columns))
+	for _, j := range columns {
+		if j < 0 || j >= nColumns {
+			return nil, nil, fmt.Errorf("learn: no column %d", j)
+		}
+		vocabularies[j] = Vocabulary(orderMapSet(sets[j]))
+	}
+	return columns, vocabularies, nil
+}
+
+// encodeColumns returns a copy in memory of data whose
+// columns in encoded are replaced by the ones described
+// there, with values returned by encode.
+func encodeColumns(data Table, encoded map[int][]Column, encode func(i, j int, e interface{}) ([]interface{}, error)) (SchemaTable, error) {
+	nRows, _ := data.Caps()
+	s := NewFrame(data).Schema()
+	schema := Schema{Label: -1}
+	for j, c := range s.Columns {
+		if j == s.Label {
+			schema.Label = len(schema.Columns)
+		}
+		if columns, ok := encoded[j]; ok {
+			schema.Columns = append(schema.Columns, columns...)
+			continue
+		}
+		schema.Columns = append(schema.Columns, c)
+	}
+	out := make(MemoryTable, nRows)
+	for i := range out {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		r := make([]interface{}, 0, len(schema.Columns))
+		for j, e := range row {
+			if _, ok := encoded[j]; !ok {
+				r = append(r, e)
+				continue
+			}
+			values, err := encode(i, j, e)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, values...)
+		}
+		out[i] = r
+	}
+	return &schemaTable{
+		MemoryTable: out,
+		schema:      schema,
+	}, nil
+}
+
+// OneHotEncoder is a Step that expands each categorical
+// column in one numeric column per label, named
+// "column=label", that is 1 for rows with that label
+// and 0 otherwise.
+//
+// Unseen labels and Missing values are encoded as all zeros.
+// Transform returns a new Table in memory.
+type OneHotEncoder struct {
+	// Columns to encode, if nil all categorical
+	// columns but the label are encoded.
+	Columns      []int
+	vocabularies map[int]Vocabulary
+}
+
+// Fit learns labels of columns to encode.
+func (o *OneHotEncoder) Fit(data Table) error {
+	_, vocabularies, err := categoricalColumns(data, o.Columns)
+	if err != nil {
+		return err
+	}
+	o.vocabularies = vocabularies
+	return nil
+}
+
+// Transform returns data with categorical
+// columns one-hot encoded.
+func (o *OneHotEncoder) Transform(data Table) (Table, error) {
+	if o.vocabularies == nil {
+		return nil, errors.New("learn: OneHotEncoder is not fitted")
+	}
+	names := NewFrame(data).Names()
+	encoded := make(map[int][]Column)
+	for j, v := range o.vocabularies {
+		if j >= len(names) {
+			continue
+		}
+		columns := make([]Column, len(v))
+		for k, l := range v {
+			columns[k] = Column{Name: names[j] + "=" + l, Type: NumericColumn}
+		}
+		encoded[j] = columns
+	}
+	return encodeColumns(data, encoded, func(i, j int, e interface{}) ([]interface{}, error) {
+		v := o.vocabularies[j]
+		values := make([]interface{}, len(v))
+		for k := range values {
+			values[k] = 0.0
+		}
+		if l, ok := categoryLabel(e); ok {
+			if k := v.Index(l); k >= 0 {
+				values[k] = 1.0
+			}
+		} else if !IsMissing(e) {
+			return nil, unknownTypeErr(e)
+		}
+		return values, nil
+	})
+}
+
+// OrdinalEncoder is a Step that replaces labels
+// of categorical columns with their index in
+// the sorted vocabulary of the column.
+//
+// Unseen labels are encoded as -1,
+// Missing values are left in place.
+// Transform returns a new Table in memory.
+type OrdinalEncoder struct {
+	// Columns to encode, if nil all categorical
+	// columns but the label are encoded.
+	Columns      []int
+	vocabularies map[int]Vocabulary
+}
+
+// Fit learns labels of columns to encode.
+func (o *OrdinalEncoder) Fit(data Table) error {
+	_, vocabularies, err := categoricalColumns(data, o.Columns)
+	if err != nil {
+		return err
+	}
+	o.vocabularies = vocabularies
+	return nil
+}
+
+// Vocabularies returns the Vocabulary
+// of each encoded column.
+func (o *OrdinalEncoder) Vocabularies() map[int]Vocabulary {
+	return o.vocabularies
+}
+
+// Transform returns data with categorical
+// columns ordinal encoded.
+func (o *OrdinalEncoder) Transform(data Table) (Table, error) {
+	if o.vocabularies == nil {
+		return nil, errors.New("learn: OrdinalEncoder is not fitted")
+	}
+	return encodeColumns(data, numericColumns(data, o.vocabularies), func(i, j int, e interface{}) ([]interface{}, error) {
+		if l, ok := categoryLabel(e); ok {
+			return []interface{}{float64(o.vocabularies[j].Index(l))}, nil
+		}
+		if IsMissing(e) {
+			return []interface{}{e}, nil
+		}
+		return nil, unknownTypeErr(e)
+	})
+}
+
+// numericColumns describes columns of data
+// in vocabularies encoded in a single numeric column.
+func numericColumns(data Table, vocabularies map[int]Vocabulary) map[int][]Column {
+	names := NewFrame(data).Names()
+	encoded := make(map[int][]Column)
+	for j := range vocabularies {
+		if j < len(names) {
+			encoded[j] = []Column{{Name: names[j], Type: NumericColumn}}
+		}
+	}
+	return encoded
+}
+
+// defaultTargetFolds is the number of folds
+// used by TargetEncoder if not specified.
+const defaultTargetFolds = 5
+
+// TargetEncoder is a Step that replaces labels of
+// categorical columns with the mean of the numeric label
+// column over training rows with that label.
+//
+// Means are smoothed toward the global mean as:
+//
+//	sum + Smoothing * global mean
+//	-----------------------------
+//	      count + Smoothing
+//
+// so that rare labels do not get extreme values.
+// Unseen labels and Missing values are encoded as the global mean.
+//
+// To not leak the label into training data, FitTransform,
+// used by Pipeline, encodes each training row with means
+// computed on the other folds, row i being in fold i % Folds.
+// Transform returns a new Table in memory.
+type TargetEncoder struct {
+	// Columns to encode, if nil all categorical
+	// columns but the label are encoded.
+	Columns   []int
+	Folds     int     // Number of folds of FitTransform, 5 if <= 1.
+	Smoothing float64 // Weight of global mean.
+	global    float64
+	means     map[int]map[string]float64
+}
+
+// Fit computes means of the label
+// for labels of columns to encode.
+func (te *TargetEncoder) Fit(data Table) error {
+	columns, label, err := te.target(data)
+	if err != nil {
+		return err
+	}
+	stats, err := accumulateTarget(data, columns, label, 1)
+	if err != nil {
+		return err
+	}
+	return te.setMeans(stats[0], columns)
+}
+
+// target returns the columns to encode
+// and the label column of data.
+func (te *TargetEncoder) target(data Table) ([]int, int, error) {
+	columns, _, err := categoricalColumns(data, te.Columns)
+	if err != nil {
+		return nil, 0, err
+	}
+	label := NewFrame(data).Schema().Label
+	if label < 0 {
+		return nil, 0, errors.New("learn: no label column")
+	}
+	return columns, label, nil
+}
+
+// targetStats stores sums and counts of the
+// label, overall and for each label of columns.
+type targetStats struct {
+	sum, count   float64
+	sums, counts map[int]map[string]float64
+}
+
+func newTargetStats(columns []int) *targetStats {
+	s := &targetStats{
+		sums:   make(map[int]map[string]float64, len(columns)),
+		counts: make(map[int]map[string]float64, len(columns)),
+	}
+	for _, j := range columns {
+		s.sums[j] = make(map[string]float64)
+		s.counts[j] = make(map[string]float64)
+	}
+	return s
+}
+
+// add adds to s the stats in o.
+func (s *targetStats) add(o *targetStats) {
+	s.sum += o.sum
+	s.count += o.count
+	for j, sums := range o.sums {
+		for l, v := range sums {
+			s.sums[j][l] += v
+			s.counts[j][l] += o.counts[j][l]
+		}
+	}
+}
+
+// without returns the stats of s
+// excluding the ones in o.
+func (s *targetStats) without(o *targetStats) *targetStats {
+	r := newTargetStats(nil)
+	r.sum, r.count = s.sum-o.sum, s.count-o.count
+	for j, sums := range s.sums {
+		r.sums[j] = make(map[string]float64, len(sums))
+		r.counts[j] = make(map[string]float64, len(sums))
+		for l, v := range sums {
+			if c := s.counts[j][l] - o.counts[j][l]; c > 0 {
+				r.sums[j][l] = v - o.sums[j][l]
+				r.counts[j][l] = c
+			}
+		}
+	}
+	return r
+}
+
+// accumulateTarget reads data once and returns the stats
+// of each of folds, row i being in fold i % folds.
+func accumulateTarget(data Table, columns []int, label, folds int) ([]*targetStats, error) {
+	stats := make([]*targetStats, folds)
+	for f := range stats {
+		stats[f] = newTargetStats(columns)
+	}
+	nRows, _ := data.Caps()
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		if label >= len(row) {
+			return nil, shortRowErr(i, label)
+		}
+		y, ok := row[label].(float64)
+		if !ok {
+			if IsMissing(row[label]) {
+				continue
+			}
+			return nil, fmt.Errorf("learn: target encoding needs a numeric label, got %T", row[label])
+		}
+		s := stats[i%folds]
+		s.sum += y
+		s.count++
+		for _, j := range columns {
+			if j >= len(row) {
+				return nil, shortRowErr(i, j)
+			}
+			if l, ok := categoryLabel(row[j]); ok {
+				s.sums[j][l] += y
+				s.counts[j][l]++
+			}
+		}
+	}
+	return stats, nil
+}
+
+// setMeans computes smoothed means from stats.
+func (te *TargetEncoder) setMeans(stats *targetStats, columns []int) error {
+	if stats.count == 0 {
+		return ErrNoData
+	}
+	te.global = stats.sum / stats.count
+	te.means = make(map[int]map[string]float64, len(columns))
+	for _, j := range columns {
+		te.means[j] = make(map[string]float64, len(stats.sums[j]))
+		for l, s := range stats.sums[j] {
+			te.means[j][l] = (s + te.Smoothing*te.global) / (stats.counts[j][l] + te.Smoothing)
+		}
+	}
+	return nil
+}
+
+// encode returns the encoding of e in j-th column.
+func (te *TargetEncoder) encode(j int, e interface{}) ([]interface{}, error) {
+	if l, ok := categoryLabel(e); ok {
+		if m, ok := te.means[j][l]; ok {
+			return []interface{}{m}, nil
+		}
+		return []interface{}{te.global}, nil
+	}
+	if IsMissing(e) {
+		return []interface{}{te.global}, nil
+	}
+	return nil, unknownTypeErr(e)
+}
+
+// Transform returns data with categorical
+// columns target encoded.
+func (te *TargetEncoder) Transform(data Table) (Table, error) {
+	if te.means == nil {
+		return nil, errors.New("learn: TargetEncoder is not fitted")
+	}
+	vocabularies := make(map[int]Vocabulary, len(te.means))
+	for j := range te.means {
+		vocabularies[j] = nil
+	}
+	return encodeColumns(data, numericColumns(data, vocabularies), func(i, j int, e interface{}) ([]interface{}, error) {
+		return te.encode(j, e)
+	})
+}
+
+// FitTransform fits te on data and returns data encoded
+// with out-of-fold means. Data is read once to compute
+// the stats of each fold and once to encode it.
+func (te *TargetEncoder) FitTransform(data Table) (Table, error) {
+	folds := te.Folds
+	if folds <= 1 {
+		folds = defaultTargetFolds
+	}
+	columns, label, err := te.target(data)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := accumulateTarget(data, columns, label, folds)
+	if err != nil {
+		return nil, err
+	}
+	total := newTargetStats(columns)
+	for _, s := range stats {
+		total.add(s)
+	}
+	if err := te.setMeans(total, columns); err != nil {
+		return nil, err
+	}
+	// Encoders of each fold,
+	// fitted on the other ones.
+	encoders := make([]*TargetEncoder, folds)
+	for f, s := range stats {
+		enc := &TargetEncoder{Smoothing: te.Smoothing}
+		if err := enc.setMeans(total.without(s), columns); err == nil {
+			encoders[f] = enc
+		}
+	}
+	vocabularies := make(map[int]Vocabulary, len(te.means))
+	for j := range te.means {
+		vocabularies[j] = nil
+	}
+	return encodeColumns(data, numericColumns(data, vocabularies), func(i, j int, e interface{}) ([]interface{}, error) {
+		if enc := encoders[i%folds]; enc != nil {
+			return enc.encode(j, e)
+		}
+		// Fold with all the data,
+		// use the whole data.
+		return te.encode(j, e)
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+var encoderData = MemoryTable{
+	{"a", 1.0, 10.0},
+	{"b", 2.0, 20.0},
+	{"a", 3.0, 30.0},
+	{"c", 4.0, 40.0},
+}
+
+func TestOneHotEncoder(t *testing.T) {
+	o := &OneHotEncoder{}
+	if err := o.Fit(encoderData); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := o.Transform(MemoryTable{{"b", 5.0, 50.0}, {"z", 6.0, Missing{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := encoded.(SchemaTable)
+	if names := st.Schema().Names(); !reflect.DeepEqual(names, []string{"c1=a", "c1=b", "c1=c", "c2", "c3"}) {
+		t.Errorf("wrong names: %v", names)
+	}
+	if st.Schema().Label != 4 {
+		t.Errorf("label at %d, want 4", st.Schema().Label)
+	}
+	expected := [][]interface{}{
+		{0.0, 1.0, 0.0, 5.0, 50.0},
+		{0.0, 0.0, 0.0, 6.0, Missing{}},
+	}
+	for i, e := range expected {
+		row, _ := encoded.Row(i)
+		if !reflect.DeepEqual(row, e) {
+			t.Errorf("row %d: expected %v, got %v", i, e, row)
+		}
+	}
+}
+
+func TestOrdinalEncoder(t *testing.T) {
+	data := cloneTable(encoderData)
+	data[1][0] = Missing{}
+	o := &OrdinalEncoder{}
+	if err := o.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := o.Transform(MemoryTable{{"c", 1.0, 0.0}, {"z", 1.0, 0.0}, {Missing{}, 1.0, 0.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []interface{}
+	for i := 0; i < 3; i++ {
+		row, _ := encoded.Row(i)
+		got = append(got, row[0])
+	}
+	if expected := []interface{}{1.0, -1.0, Missing{}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestTargetEncoder(t *testing.T) {
+	te := &TargetEncoder{Smoothing: 1}
+	if err := te.Fit(encoderData); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := te.Transform(MemoryTable{{"a", 0.0}, {"z", 0.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Global mean is 25, "a" has mean 20 on 2 rows.
+	a, _ := encoded.Row(0)
+	z, _ := encoded.Row(1)
+	if !floatsAreEqual(a[0].(float64), (40.0+25)/3) || z[0] != 25.0 {
+		t.Errorf("wrong encodings: %v, %v", a[0], z[0])
+	}
+}
+
+func TestTargetEncoder_outOfFold(t *testing.T) {
+	te := &TargetEncoder{Folds: 2}
+	encoded, err := NewPipeline(te).FitTransform(cloneTable(encoderData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Row 0 is encoded with rows 1 and 3 where "a"
+	// is unseen, row 2 with the same rows.
+	var got []interface{}
+	for i := 0; i < 4; i++ {
+		row, _ := encoded.Row(i)
+		got = append(got, row[0])
+	}
+	expected := []interface{}{30.0, 20.0, 30.0, 20.0}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestEncoders_ragged(t *testing.T) {
+	data := MemoryTable{{"a", 1.0, 10.0}, {"b", 2.0, 20.0, "x"}, {"a", 3.0}}
+	o := &OneHotEncoder{}
+	if err := o.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	te := &TargetEncoder{}
+	if _, err := te.FitTransform(data); err == nil || err.Error() != "learn: row 2 has no column 2" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -13,6 +13,39 @@ import (
 func unknownTypeErr(a interface{}) error {
 	return fmt.Errorf("learn: type of \"%v\" must be float or string not %T", a, a)
 }
+
+// missingValueErr assembles an error for
+// a missing value where it is not supported.
+func missingValueErr(row, column int) error {
+	return fmt.Errorf("learn: missing value in row %d, column %d, use an Imputer to fill it", row, column)
+}
+
+// shortRowErr assembles an error for
+// a row without the expected column.
+func shortRowErr(row, column int) error {
+	return fmt.Errorf("learn: row %d has no column %d", row, column)
+}
+
+// rowWidthErr assembles an error for a row
+// with a number of values different from
+// the number of Table's columns.
+func rowWidthErr(row, values, columns int) error {
+	return fmt.Errorf("learn: row %d has %d values, expected %d", row, values, columns)
+}
+
 func typeMismatchErr(a, b interface{}) error {
-	return fmt.Errorf("learn: type mismatch in features \"v\" \"v\"\n", a, b)
+	return fmt.Errorf("learn: type mismatch in features \"%v\" \"%v\"", a, b)
+}
+
+// ParseError is returned when a value
+// cannot be stored with the type of its column.
+type ParseError struct {
+	Row    int    // Record of the value in the file, starting at 1, its line for FileTable.
+	Column int    // Column of the value, starting at 1.
+	Value  string // The value that could not be parsed.
+	Type   ColumnType
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("learn: row %d, column %d: cannot parse %q as %s", e.Row, e.Column, e.Value, e.Type)
 }
@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// predictionColumn is the name of the column
+// of predicted values in written headers.
+const predictionColumn = "prediction"
+
+// missingMarker is written for Missing
+// values if no marker is specified.
+const missingMarker = "?"
+
+// WriteCSV writes data's rows to w as CSV records.
+//
+// Numbers are written with the minimum precision
+// needed to read them back unchanged, categories
+// are written as their labels and Missing values
+// as the first of opts.Missing markers,
+// or as "?" if there are none, so that they are read
+// back by ReadCSV or ReadAllCSV with the same marker.
+//
+// opts.Comma sets the field delimiter. If opts.Header is true
+// and data is a SchemaTable, columns' names are written first.
+// Other options are ignored.
+func WriteCSV(w io.Writer, data Table, opts *CSVOptions) error {
+	return writeCSV(w, data, nil, opts)
+}
+
+// WriteClassifierCSV is like WriteCSV but appends to
+// each row of data the label predicted by a Classifier,
+// stored in the same row of predicted.
+func WriteClassifierCSV(w io.Writer, data, predicted Table, opts *CSVOptions) error {
+	return writeCSV(w, data, func(i int) (interface{}, error) {
+		row, err := predicted.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		if len(row) == 0 {
+			return nil, ErrNoData
+		}
+		return row[0], nil
+	}, opts)
+}
+
+// WriteRegressionCSV is like WriteCSV but appends to
+// each row of data the value predicted by a Regression.
+func WriteRegressionCSV(w io.Writer, data Table, predicted []float64, opts *CSVOptions) error {
+	nRows, _ := data.Caps()
+	if nRows != len(predicted) {
+		return fmt.Errorf("learn: %d rows but %d predictions", nRows, len(predicted))
+	}
+	return writeCSV(w, data, func(i int) (interface{}, error) {
+		return predicted[i], nil
+	}, opts)
+}
+
+// writeCSV writes data's rows, appending the value
+// returned by prediction if it is not nil.
+func writeCSV(w io.Writer, data Table, prediction func(int) (interface{}, error), opts *CSVOptions) error {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	missing := missingMarker
+	if len(opts.Missing) > 0 {
+		missing = opts.Missing[0]
+	}
+	if st, ok := data.(SchemaTable); ok && opts.Header {
+		header := st.Schema().Names()
+		if prediction != nil {
+			header = append(header, predictionColumn)
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	nRows, _ := data.Caps()
+	var record []string
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		record = record[:0]
+		for _, e := range row {
+			s, err := formatValue(e, missing)
+			if err != nil {
+				return err
+			}
+			record = append(record, s)
+		}
+		if prediction != nil {
+			p, err := prediction(i)
+			if err != nil {
+				return err
+			}
+			s, err := formatValue(p, missing)
+			if err != nil {
+				return err
+			}
+			record = append(record, s)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatValue returns the CSV representation
+// of an element of a row.
+func formatValue(e interface{}, missing string) (string, error) {
+	switch v := e.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return v, nil
+	case *Category:
+		return v.label, nil
+	case Missing:
+		return missing, nil
+	default:
+		return "", unknownTypeErr(e)
+	}
+}
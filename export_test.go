@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	data, err := ReadCSVFrom(bytes.NewBufferString("x,kind\n1.5,foo\n?,bar\n"), &CSVOptions{
+		Header:  true,
+		Missing: []string{"?"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = Normalize(data, []float64{0, 0}, []float64{1, 1}, []Vocabulary{nil, NewVocabulary("bar", "foo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &CSVOptions{Header: true, Comma: ';', Missing: []string{"NA"}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, data, opts); err != nil {
+		t.Fatal(err)
+	}
+	expected := "x;kind\n1.5;foo\nNA;bar\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+	buf.Reset()
+	if err := WriteRegressionCSV(&buf, data, []float64{0.25, 3}, opts); err != nil {
+		t.Fatal(err)
+	}
+	expected = "x;kind;prediction\n1.5;foo;0.25\nNA;bar;3\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+	buf.Reset()
+	predicted := MemoryTable{{"a"}, {"b"}}
+	if err := WriteClassifierCSV(&buf, data, predicted, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected = "1.5,foo,a\n?,bar,b\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteCSV_roundTrip(t *testing.T) {
+	data := MemoryTable{{1.5, "foo"}, {Missing{}, "bar"}, {2.0, Missing{}}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, data, nil); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadAllCSVFrom(bytes.NewReader(buf.Bytes()), "?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, data) {
+		t.Errorf("expected %v, got %v", data, read)
+	}
+	st, err := ReadCSVFrom(bytes.NewReader(buf.Bytes()), &CSVOptions{Missing: []string{"?"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range data {
+		if row, _ := st.Row(i); !reflect.DeepEqual(row, expected) {
+			t.Errorf("expected %v, got %v", expected, row)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// FeatureScore scores how much a feature is useful
+// to predict a label, given their values on the same rows.
+// Higher scores are better.
+type FeatureScore func(feature, label []interface{}) (float64, error)
+
+// miBins is the maximum number of bins
+// numeric values are discretized in by MutualInfoScore.
+const miBins = 10
+
+// valueKey returns a key identifying
+// a value as a category.
+func valueKey(e interface{}) (string, bool) {
+	if l, ok := categoryLabel(e); ok {
+		return l, true
+	}
+	if v, ok := e.(float64); ok {
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// VarianceScore scores features by their variance,
+// label is ignored. Categorical features score
+// the fraction of values different from the mode,
+// so constant features always score 0.
+func VarianceScore(feature, label []interface{}) (float64, error) {
+	var s ColumnStats
+	for _, e := range feature {
+		if err := s.Add(e); err != nil {
+			return 0, err
+		}
+	}
+	if s.Count == 0 {
+		return 0, nil
+	}
+	if s.Categorical() {
+		top := s.top(1)
+		return 1 - float64(top[0].Count)/float64(s.Count), nil
+	}
+	if s.Count < 2 {
+		return 0, nil
+	}
+	return s.Variance(), nil
+}
+
+// ChiSquaredScore scores features by the chi-squared
+// statistic of independence between feature and label.
+// Both are treated as categorical, so continuous
+// numeric features should be binned first.
+// Rows with Missing values are ignored.
+func ChiSquaredScore(feature, label []interface{}) (float64, error) {
+	table, rows, columns, n, err := contingency(feature, label, valueKey, valueKey)
+	if err != nil {
+		return 0, err
+	}
+	var chi2 float64
+	for f, counts := range table {
+		for l, e := range columns {
+			expected := rows[f] * e / n
+			d := counts[l] - expected
+			chi2 += d * d / expected
+		}
+	}
+	return chi2, nil
+}
+
+// contingency returns counts of pairs of feature's and label's keys,
+// totals of each key and the number of pairs.
+func contingency(feature, label []interface{}, fKey, lKey func(interface{}) (string, bool)) (table map[string]map[string]float64, rows, columns map[string]float64, n float64, err error) {
+	if len(feature) != len(label) {
+		return nil, nil, nil, 0, errors.New("learn: feature and label have different lengths")
+	}
+	table = make(map[string]map[string]float64)
+	rows = make(map[string]float64)
+	columns = make(map[string]float64)
+	for i, e := range feature {
+		if IsMissing(e) || IsMissing(label[i]) {
+			continue
+		}
+		f, ok := fKey(e)
+		if !ok {
+			return nil, nil, nil, 0, unknownTypeErr(e)
+		}
+		l, ok := lKey(label[i])
+		if !ok {
+			return nil, nil, nil, 0, unknownTypeErr(label[i])
+		}
+		if table[f] == nil {
+			table[f] = make(map[string]float64)
+		}
+		table[f][l]++
+		rows[f]++
+		columns[l]++
+		n++
+	}
+	if n == 0 {
+		return nil, nil, nil, 0, ErrNoData
+	}
+	return table, rows, columns, n, nil
+}
+
+// ANOVAFScore scores numeric features by the F statistic
+// of the one-way analysis of variance of feature's values
+// grouped by label. Rows with Missing values are ignored.
+// Features with the same mean in each group score 0,
+// +Inf if they are constant within groups.
+func ANOVAFScore(feature, label []interface{}) (float64, error) {
+	if len(feature) != len(label) {
+		return 0, errors.New("learn: feature and label have different lengths")
+	}
+	groups := make(map[string]*ColumnStats)
+	var all ColumnStats
+	for i, e := range feature {
+		if IsMissing(e) || IsMissing(label[i]) {
+			continue
+		}
+		v, ok := e.(float64)
+		if !ok {
+			return 0, errors.New("learn: ANOVA F needs numeric features")
+		}
+		l, ok := valueKey(label[i])
+		if !ok {
+			return 0, unknownTypeErr(label[i])
+		}
+		if groups[l] == nil {
+			groups[l] = &ColumnStats{}
+		}
+		groups[l].Add(v)
+		all.Add(v)
+	}
+	k, n := float64(len(groups)), float64(all.Count)
+	if k < 2 || n <= k {
+		return 0, ErrNoData
+	}
+	var between, within float64
+	for _, g := range groups {
+		d := g.Mean() - all.Mean()
+		between += float64(g.Count) * d * d
+		within += g.m2
+	}
+	switch {
+	case between == 0:
+		// Constant feature or same
+		// mean in every group.
+		return 0, nil
+	case within == 0:
+		// Groups perfectly separated.
+		return math.Inf(1), nil
+	}
+	return (between / (k - 1)) / (within / (n - k)), nil
+}
+
+// MutualInfoScore scores features by their mutual
+// information, in nats, with label. Numeric features
+// and labels are discretized in up to 10 bins of
+// equal frequency, so features of mixed types can be compared.
+// Rows with Missing values are ignored.
+func MutualInfoScore(feature, label []interface{}) (float64, error) {
+	table, rows, columns, n, err := contingency(feature, label, binKey(feature), binKey(label))
+	if err != nil {
+		return 0, err
+	}
+	var mi float64
+	for f, counts := range table {
+		for l, c := range counts {
+			mi += c / n * math.Log(c*n/(rows[f]*columns[l]))
+		}
+	}
+	return mi, nil
+}
+
+// binKey returns a key function that maps numeric
+// values to equal frequency bins of values
+// and categorical ones to their label.
+func binKey(values []interface{}) func(interface{}) (string, bool) {
+	var numbers []float64
+	distinct := make(map[float64]struct{})
+	for _, e := range values {
+		if v, ok := e.(float64); ok {
+			numbers = append(numbers, v)
+			distinct[v] = struct{}{}
+		}
+	}
+	bins := miBins
+	if len(distinct) < bins {
+		bins = len(distinct)
+	}
+	sort.Float64s(numbers)
+	cuts := make([]float64, 0, bins)
+	for b := 1; b < bins; b++ {
+		cuts = append(cuts, quantile(numbers, float64(b)/float64(bins)))
+	}
+	return func(e interface{}) (string, bool) {
+		if v, ok := e.(float64); ok {
+			if len(distinct) <= miBins {
+				return strconv.FormatFloat(v, 'g', -1, 64), true
+			}
+			return "#" + strconv.Itoa(sort.SearchFloat64s(cuts, v)), true
+		}
+		return categoryLabel(e)
+	}
+}
+
+// FeatureSelector is a Step that keeps the columns of a Table
+// with the best scores. Columns that are not candidates,
+// like the label, are always kept.
+//
+// If K > 0 the K candidates with highest scores are kept,
+// otherwise the ones scoring more than Threshold.
+// Candidates that cannot be scored, like the ones
+// with only Missing values, are removed.
+// Tables passed to Transform must have the columns of
+// training data, the label can be missing if it is the last one.
+type FeatureSelector struct {
+	Score     FeatureScore
+	K         int
+	Threshold float64
+	// Columns that can be removed, if nil
+	// all columns but the label.
+	Columns []int
+	scores  []float64
+	keep    []int
+}
+
+// Fit scores candidate columns of data
+// against its label and selects them.
+func (fs *FeatureSelector) Fit(data Table) error {
+	if fs.Score == nil {
+		return errors.New("learn: no feature score")
+	}
+	f := NewFrame(data)
+	_, nColumns := f.Caps()
+	label := f.Schema().Label
+	candidates := fs.Columns
+	if candidates == nil {
+		for j := 0; j < nColumns; j++ {
+			if j != label {
+				candidates = append(candidates, j)
+			}
+		}
+	}
+	var y []interface{}
+	if label >= 0 {
+		var err error
+		y, err = f.Column(label)
+		if err != nil {
+			return err
+		}
+	}
+	fs.scores = make([]float64, nColumns)
+	for j := range fs.scores {
+		fs.scores[j] = math.NaN()
+	}
+	for _, j := range candidates {
+		x, err := f.Column(j)
+		if err != nil {
+			return err
+		}
+		if y == nil {
+			// No label, only scores
+			// that ignore it can work.
+			y = make([]interface{}, len(x))
+			for i := range y {
+				y[i] = Missing{}
+			}
+		}
+		fs.scores[j], err = fs.Score(x, y)
+		if err == ErrNoData {
+			// Nothing to score, e.g. only
+			// Missing values, never selected.
+			fs.scores[j] = math.NaN()
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	selected := make(map[int]bool, len(candidates))
+	for _, j := range candidates {
+		selected[j] = false
+	}
+	if fs.K > 0 {
+		best := make([]int, len(candidates))
+		copy(best, candidates)
+		// NaN scores go last.
+		sort.SliceStable(best, func(a, b int) bool {
+			sa, sb := fs.scores[best[a]], fs.scores[best[b]]
+			if math.IsNaN(sb) {
+				return !math.IsNaN(sa)
+			}
+			return sa > sb
+		})
+		if len(best) > fs.K {
+			best = best[:fs.K]
+		}
+		for _, j := range best {
+			selected[j] = !math.IsNaN(fs.scores[j])
+		}
+	} else {
+		for _, j := range candidates {
+			selected[j] = fs.scores[j] > fs.Threshold
+		}
+	}
+	fs.keep = nil
+	for j := 0; j < nColumns; j++ {
+		if s, ok := selected[j]; !ok || s {
+			fs.keep = append(fs.keep, j)
+		}
+	}
+	return nil
+}
+
+// Scores returns the score of each column, NaN for
+// columns that are not candidates or have nothing to score.
+func (fs *FeatureSelector) Scores() []float64 {
+	return fs.scores
+}
+
+// Selected returns indexes of kept columns.
+func (fs *FeatureSelector) Selected() []int {
+	return fs.keep
+}
+
+// Transform returns a Frame of data
+// with the selected columns.
+func (fs *FeatureSelector) Transform(data Table) (Table, error) {
+	if fs.scores == nil {
+		return nil, errors.New("learn: FeatureSelector is not fitted")
+	}
+	f := NewFrame(data)
+	_, nColumns := f.Caps()
+	var keep []int
+	for _, j := range fs.keep {
+		if j < nColumns {
+			keep = append(keep, j)
+		}
+	}
+	return f.project(keep), nil
+}
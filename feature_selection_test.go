@@ -0,0 +1,145 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var selectionData = MemoryTable{
+	// Informative, constant, noise, label.
+	{1.0, 7.0, "x", "a"},
+	{1.2, 7.0, "y", "a"},
+	{0.8, 7.0, "x", "a"},
+	{5.0, 7.0, "y", "b"},
+	{5.3, 7.0, "x", "b"},
+	{4.9, 7.0, "y", "b"},
+}
+
+func TestFeatureScores(t *testing.T) {
+	data := NewFrame(selectionData)
+	label, _ := data.Column(3)
+	informative, _ := data.Column(0)
+	noise, _ := data.Column(2)
+	constant, _ := data.Column(1)
+	cases := []struct {
+		name  string
+		score FeatureScore
+	}{
+		{"chi2", ChiSquaredScore},
+		{"mi", MutualInfoScore},
+	}
+	for _, c := range cases {
+		good, err := c.score(informative, label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad, err := c.score(noise, label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if good <= bad {
+			t.Errorf("%s: informative feature scores %v, noise %v", c.name, good, bad)
+		}
+	}
+	// Both classes have 3 rows, "x" and "y" are
+	// not balanced among them.
+	mi, _ := MutualInfoScore(noise, label)
+	if !floatsAreEqual(mi, 2*(2.0/6*math.Log(4.0/3))+2*(1.0/6*math.Log(2.0/3))) {
+		t.Errorf("wrong mutual information %v", mi)
+	}
+	f, err := ANOVAFScore(informative, label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f < 100 {
+		t.Errorf("expected a large F, got %v", f)
+	}
+	if _, err := ANOVAFScore(noise, label); err == nil {
+		t.Error("ANOVA F on categorical feature must fail")
+	}
+	v, _ := VarianceScore(constant, nil)
+	if v != 0 {
+		t.Errorf("constant feature has variance %v", v)
+	}
+}
+
+func TestFeatureSelector(t *testing.T) {
+	data := selectionData
+	fs := &FeatureSelector{Score: VarianceScore}
+	if err := fs.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{0, 2, 3}; !reflect.DeepEqual(fs.Selected(), expected) {
+		t.Errorf("expected %v, got %v", expected, fs.Selected())
+	}
+	fs = &FeatureSelector{Score: MutualInfoScore, K: 1}
+	selected, err := NewPipeline(fs).FitTransform(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := selected.Row(0)
+	if !reflect.DeepEqual(row, []interface{}{1.0, "a"}) {
+		t.Errorf("wrong selected row %v", row)
+	}
+	// Test data without label.
+	selected, err = fs.Transform(MemoryTable{{2.0, 7.0, "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ = selected.Row(0)
+	if !reflect.DeepEqual(row, []interface{}{2.0}) {
+		t.Errorf("wrong selected row %v", row)
+	}
+}
+
+// A constant feature must never beat an informative
+// one, even if it is constant within groups.
+func TestFeatureSelector_constant(t *testing.T) {
+	data := MemoryTable{
+		{1.0, 7.0, "a"},
+		{1.5, 7.0, "a"},
+		{2.0, 7.0, "a"},
+		{5.0, 7.0, "b"},
+		{5.5, 7.0, "b"},
+		{6.5, 7.0, "b"},
+	}
+	fs := &FeatureSelector{Score: ANOVAFScore, K: 1}
+	if err := fs.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if s := fs.Scores()[1]; s != 0 {
+		t.Errorf("constant feature scores %v", s)
+	}
+	if expected := []int{0, 2}; !reflect.DeepEqual(fs.Selected(), expected) {
+		t.Errorf("expected %v, got %v", expected, fs.Selected())
+	}
+}
+
+func TestFeatureSelector_missing(t *testing.T) {
+	data := MemoryTable{
+		{Missing{}, 1.0, 7.0, "a"},
+		{Missing{}, 2.0, 7.0, "a"},
+		{Missing{}, 5.0, 7.0, "b"},
+		{Missing{}, 6.5, 7.0, "b"},
+	}
+	for _, fs := range []*FeatureSelector{
+		{Score: ANOVAFScore, K: 3},
+		{Score: ChiSquaredScore, K: 3},
+		{Score: ANOVAFScore, Threshold: -1},
+	} {
+		if err := fs.Fit(data); err != nil {
+			t.Fatal(err)
+		}
+		if !math.IsNaN(fs.Scores()[0]) {
+			t.Errorf("expected NaN score, got %v", fs.Scores()[0])
+		}
+		if fs.Selected()[0] == 0 {
+			t.Errorf("column of Missing values selected: %v", fs.Selected())
+		}
+	}
+}
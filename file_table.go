@@ -0,0 +1,295 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// defaultCacheSize is the number of parsed rows
+// kept in memory by FileTable if not specified.
+const defaultCacheSize = 1024
+
+// FileTable is a SchemaTable backed by a CSV file.
+// Only offsets of records are kept in memory,
+// rows are read and parsed when requested and
+// the most recently used are cached.
+//
+// Rows replaced with Update are stored in a temporary file,
+// so Normalize can be used on files larger than memory.
+//
+// FileTable is not safe for concurrent use,
+// Close must be called to release its files.
+type FileTable struct {
+	f       *os.File
+	offsets []int64 // Start of each record, plus end of file.
+	lines   []int   // Line of each record in file, starting at 1.
+	decoder *csvDecoder
+	cache   *rowCache
+	// spill stores updated rows,
+	// updated maps them to their position.
+	spill   *os.File
+	updated map[int]spillEntry
+}
+
+type spillEntry struct {
+	offset int64
+	length int
+}
+
+// OpenCSV indexes the CSV file at path
+// returning a FileTable that reads it lazily.
+// The whole file is scanned once to find records' offsets
+// and to infer columns' types, as in ReadCSV.
+// cacheSize is the number of parsed rows kept in memory,
+// if <= 0 a default is used.
+//
+// Compressed files are not supported, as records
+// are read at random offsets, use ReadCSVFrom for them.
+func OpenCSV(path string, opts *CSVOptions, cacheSize int) (*FileTable, error) {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &FileTable{
+		f:       f,
+		decoder: newCSVDecoder(opts),
+		cache:   newRowCache(cacheSize),
+		updated: make(map[int]spillEntry),
+	}
+	header, err := t.index(opts.Header)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := t.decoder.makeSchema(opts.Schema, header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// index scans the file storing records' offsets
+// and observing their values to infer types.
+// Header, if present, is returned and not indexed.
+func (t *FileTable) index(hasHeader bool) ([]string, error) {
+	var header []string
+	var offset int64
+	line := 1
+	r := bufio.NewReader(t.f)
+	for {
+		b, err := readRecord(r, t.decoder.opts.Comment)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(b)) > 0 {
+			record, pErr := t.decoder.parseRecord(b)
+			switch {
+			case pErr == io.EOF:
+				// A comment, nothing to index.
+			case pErr != nil:
+				return nil, pErr
+			case hasHeader && header == nil:
+				header = record
+			default:
+				t.offsets = append(t.offsets, offset)
+				t.lines = append(t.lines, line)
+				t.decoder.observe(record)
+			}
+		}
+		offset += int64(len(b))
+		line += bytes.Count(b, []byte{'\n'})
+		if err == io.EOF {
+			break
+		}
+	}
+	t.offsets = append(t.offsets, offset)
+	if hasHeader && header == nil {
+		return nil, ErrNoData
+	}
+	return header, nil
+}
+
+// readRecord reads a whole CSV record
+// that can span multiple lines
+// if new lines are quoted.
+// Lines starting with comment, if not zero,
+// are returned alone as quotes in them
+// do not delimit fields.
+func readRecord(r *bufio.Reader, comment rune) ([]byte, error) {
+	var record []byte
+	quotes := 0
+	for {
+		line, err := r.ReadBytes('\n')
+		if record == nil && comment != 0 && bytes.HasPrefix(line, []byte(string(comment))) {
+			return line, err
+		}
+		record = append(record, line...)
+		quotes += bytes.Count(line, []byte{'"'})
+		if err != nil || quotes%2 == 0 {
+			return record, err
+		}
+	}
+}
+
+// Caps implements Table's Caps.
+func (t *FileTable) Caps() (int, int) {
+	return len(t.offsets) - 1, len(t.decoder.schema.Columns)
+}
+
+// Schema implements SchemaTable's Schema.
+func (t *FileTable) Schema() Schema {
+	return t.decoder.schema
+}
+
+// Row implements Table's Row.
+// Returned row is a copy, changes to it
+// are stored only passing it to Update.
+func (t *FileTable) Row(i int) ([]interface{}, error) {
+	if i < 0 || i >= len(t.offsets)-1 {
+		return nil, ErrNoData
+	}
+	if row, ok := t.cache.get(i); ok {
+		return copyRow(row), nil
+	}
+	var row []interface{}
+	var err error
+	if e, ok := t.updated[i]; ok {
+		row, err = t.readSpilled(e)
+	} else {
+		row, err = t.readRow(i)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.cache.put(i, row)
+	return copyRow(row), nil
+}
+
+func (t *FileTable) readRow(i int) ([]interface{}, error) {
+	b := make([]byte, t.offsets[i+1]-t.offsets[i])
+	if _, err := t.f.ReadAt(b, t.offsets[i]); err != nil {
+		return nil, err
+	}
+	record, err := t.decoder.parseRecord(b)
+	if err != nil {
+		return nil, err
+	}
+	return t.decoder.decode(record, t.lines[i])
+}
+
+func (t *FileTable) readSpilled(e spillEntry) ([]interface{}, error) {
+	b := make([]byte, e.length)
+	if _, err := t.spill.ReadAt(b, e.offset); err != nil {
+		return nil, err
+	}
+	var row []interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// Update implements Table's Update.
+func (t *FileTable) Update(i int, r []interface{}) error {
+	if i < 0 || i >= len(t.offsets)-1 {
+		return ErrNoData
+	}
+	if t.spill == nil {
+		f, err := ioutil.TempFile("", "learn")
+		if err != nil {
+			return err
+		}
+		// The file is removed
+		// but kept open until Close.
+		os.Remove(f.Name())
+		t.spill = f
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		return err
+	}
+	offset, err := t.spill.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := t.spill.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	t.updated[i] = spillEntry{offset: offset, length: buf.Len()}
+	t.cache.put(i, copyRow(r))
+	return nil
+}
+
+// Close closes underlying files.
+func (t *FileTable) Close() error {
+	if t.spill != nil {
+		t.spill.Close()
+	}
+	return t.f.Close()
+}
+
+// rowCache is a least recently used
+// cache of parsed rows.
+type rowCache struct {
+	size  int
+	order *list.List // Front is the most recently used.
+	items map[int]*list.Element
+}
+
+type cachedRow struct {
+	i   int
+	row []interface{}
+}
+
+func newRowCache(size int) *rowCache {
+	return &rowCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[int]*list.Element),
+	}
+}
+
+func (c *rowCache) get(i int) ([]interface{}, bool) {
+	e, ok := c.items[i]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cachedRow).row, true
+}
+
+func (c *rowCache) put(i int, row []interface{}) {
+	if e, ok := c.items[i]; ok {
+		e.Value.(*cachedRow).row = row
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[i] = c.order.PushFront(&cachedRow{i: i, row: row})
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*cachedRow).i)
+	}
+}
+
+func init() {
+	// Types stored in rows
+	// must be known to gob.
+	gob.Register(&Category{})
+	gob.Register(Missing{})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFileTable(t *testing.T) {
+	memory, err := ReadCSV("datasets/iris.csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A small cache forces
+	// rows to be read again.
+	file, err := OpenCSV("datasets/iris.csv", nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if !reflect.DeepEqual(memory.Schema(), file.Schema()) {
+		t.Fatalf("expected schema %v, got %v", memory.Schema(), file.Schema())
+	}
+	mRows, mColumns := memory.Caps()
+	fRows, fColumns := file.Caps()
+	if mRows != fRows || mColumns != fColumns {
+		t.Fatalf("expected caps %d, %d got %d, %d", mRows, mColumns, fRows, fColumns)
+	}
+	_, _, _, err = Normalize(memory, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, err = Normalize(file, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < mRows; i++ {
+		m, err := memory.Row(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := file.Row(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, f) {
+			t.Fatalf("row %d: expected %v, got %v", i, m, f)
+		}
+	}
+}
+
+func TestFileTable_comments(t *testing.T) {
+	path := tempCSV(t, "a,b\n# a \"quote\n\n1,x\n2,y\nfoo,z\n")
+	defer os.Remove(path)
+	opts := &CSVOptions{
+		Header:  true,
+		Comment: '#',
+		Schema:  &Schema{Columns: []Column{{Type: NumericColumn}, {}}, Label: 1},
+	}
+	file, err := OpenCSV(path, opts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if n, _ := file.Caps(); n != 3 {
+		t.Fatalf("expected 3 rows, got %d", n)
+	}
+	row, err := file.Row(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(row, []interface{}{2.0, "y"}) {
+		t.Fatalf("unexpected row %v", row)
+	}
+	// Rows are copies.
+	row[0] = 42.0
+	if row, _ = file.Row(1); row[0] != 2.0 {
+		t.Errorf("cached row modified: %v", row)
+	}
+	_, err = file.Row(2)
+	if e, ok := err.(*ParseError); !ok || e.Row != 6 {
+		t.Errorf("expected a ParseError at line 6, got %v", err)
+	}
+}
+
+func TestRowCache(t *testing.T) {
+	c := newRowCache(2)
+	c.put(0, []interface{}{0.0})
+	c.put(1, []interface{}{1.0})
+	c.get(0)
+	c.put(2, []interface{}{2.0})
+	if _, ok := c.get(1); ok {
+		t.Fatal("least recently used row must be evicted")
+	}
+	if _, ok := c.get(0); !ok {
+		t.Fatal("recently used row must be kept")
+	}
+}
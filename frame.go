@@ -0,0 +1,415 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Frame is a view of a Table with named columns
+// that can be sliced by rows and columns
+// without copying data.
+//
+// Rows returned by a Frame are copies,
+// Update writes selected columns back
+// to the underlying Table.
+type Frame struct {
+	t       Table
+	rows    []int // Indexes of rows in t, nil means all.
+	columns []int // Indexes of columns in t.
+	schema  Schema
+}
+
+// NewFrame returns a Frame of all t's rows and columns.
+// Names and label are taken from Schema if t is a SchemaTable,
+// otherwise columns are named c1...cn and the last one is the label.
+func NewFrame(t Table) *Frame {
+	_, nColumns := t.Caps()
+	f := &Frame{
+		t:       t,
+		columns: make([]int, nColumns),
+	}
+	for j := range f.columns {
+		f.columns[j] = j
+	}
+	if st, ok := t.(SchemaTable); ok {
+		s := st.Schema()
+		f.schema.Columns = make([]Column, len(s.Columns))
+		copy(f.schema.Columns, s.Columns)
+		f.schema.Label = s.Label
+		return f
+	}
+	f.schema = defaultSchema(nColumns)
+	return f
+}
+
+// defaultSchema returns the Schema of n columns
+// named c1...cn with the last one as label.
+func defaultSchema(n int) Schema {
+	s := Schema{
+		Columns: make([]Column, n),
+		Label:   n - 1,
+	}
+	for j := range s.Columns {
+		s.Columns[j].Name = "c" + strconv.Itoa(j+1)
+	}
+	return s
+}
+
+// Caps implements Table's Caps.
+func (f *Frame) Caps() (int, int) {
+	if f.rows != nil {
+		return len(f.rows), len(f.columns)
+	}
+	nRows, _ := f.t.Caps()
+	return nRows, len(f.columns)
+}
+
+// Schema implements SchemaTable's Schema.
+func (f *Frame) Schema() Schema {
+	return f.schema
+}
+
+// Names returns columns' names.
+func (f *Frame) Names() []string {
+	return f.schema.Names()
+}
+
+// Index returns the index of the column
+// named name, -1 if there is none.
+func (f *Frame) Index(name string) int {
+	for j, c := range f.schema.Columns {
+		if c.Name == name {
+			return j
+		}
+	}
+	return -1
+}
+
+// row maps i-th row of f to
+// the one of underlying Table.
+func (f *Frame) row(i int) (int, error) {
+	if f.rows == nil {
+		return i, nil
+	}
+	if i < 0 || i >= len(f.rows) {
+		return 0, ErrNoData
+	}
+	return f.rows[i], nil
+}
+
+// Row implements Table's Row.
+func (f *Frame) Row(i int) ([]interface{}, error) {
+	ri, err := f.row(i)
+	if err != nil {
+		return nil, err
+	}
+	r, err := f.t.Row(ri)
+	if err != nil {
+		return nil, err
+	}
+	row := make([]interface{}, len(f.columns))
+	for j, c := range f.columns {
+		if c >= len(r) {
+			return nil, ErrNoData
+		}
+		row[j] = r[c]
+	}
+	return row, nil
+}
+
+// Update implements Table's Update.
+// Columns not in f are left unchanged.
+func (f *Frame) Update(i int, r []interface{}) error {
+	if len(r) != len(f.columns) {
+		return fmt.Errorf("learn: row has %d columns, want %d", len(r), len(f.columns))
+	}
+	ri, err := f.row(i)
+	if err != nil {
+		return err
+	}
+	row, err := f.t.Row(ri)
+	if err != nil {
+		return err
+	}
+	updated := make([]interface{}, len(row))
+	copy(updated, row)
+	for j, c := range f.columns {
+		updated[c] = r[j]
+	}
+	return f.t.Update(ri, updated)
+}
+
+// Column returns values of j-th column.
+func (f *Frame) Column(j int) ([]interface{}, error) {
+	if j < 0 || j >= len(f.columns) {
+		return nil, fmt.Errorf("learn: no column %d", j)
+	}
+	nRows, _ := f.Caps()
+	values := make([]interface{}, nRows)
+	for i := range values {
+		row, err := f.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = row[j]
+	}
+	return values, nil
+}
+
+// project returns a Frame of the same rows
+// and the columns of f at indexes.
+func (f *Frame) project(indexes []int) *Frame {
+	p := &Frame{
+		t:       f.t,
+		rows:    f.rows,
+		columns: make([]int, len(indexes)),
+		schema: Schema{
+			Columns: make([]Column, len(indexes)),
+			Label:   -1,
+		},
+	}
+	for k, j := range indexes {
+		p.columns[k] = f.columns[j]
+		p.schema.Columns[k] = f.schema.Columns[j]
+		if j == f.schema.Label {
+			p.schema.Label = k
+		}
+	}
+	return p
+}
+
+// Select returns a Frame of the named columns,
+// in the order they are passed.
+func (f *Frame) Select(names ...string) (*Frame, error) {
+	indexes := make([]int, len(names))
+	for k, name := range names {
+		j := f.Index(name)
+		if j < 0 {
+			return nil, fmt.Errorf("learn: no column named %q", name)
+		}
+		indexes[k] = j
+	}
+	return f.project(indexes), nil
+}
+
+// Drop returns a Frame without the named columns.
+func (f *Frame) Drop(names ...string) (*Frame, error) {
+	drop := make(map[int]bool, len(names))
+	for _, name := range names {
+		j := f.Index(name)
+		if j < 0 {
+			return nil, fmt.Errorf("learn: no column named %q", name)
+		}
+		drop[j] = true
+	}
+	var indexes []int
+	for j := range f.columns {
+		if !drop[j] {
+			indexes = append(indexes, j)
+		}
+	}
+	return f.project(indexes), nil
+}
+
+// Filter returns a Frame of the rows
+// for which keep returns true.
+func (f *Frame) Filter(keep func(row []interface{}) bool) (*Frame, error) {
+	nRows, _ := f.Caps()
+	rows := make([]int, 0)
+	for i := 0; i < nRows; i++ {
+		row, err := f.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		if !keep(row) {
+			continue
+		}
+		ri, _ := f.row(i)
+		rows = append(rows, ri)
+	}
+	p := f.project(allColumns(len(f.columns)))
+	p.rows = rows
+	return p, nil
+}
+
+// Split returns a Frame of features
+// and one of the label column.
+func (f *Frame) Split() (features, label *Frame, err error) {
+	if f.schema.Label < 0 {
+		return nil, nil, errors.New("learn: no label column")
+	}
+	var indexes []int
+	for j := range f.columns {
+		if j != f.schema.Label {
+			indexes = append(indexes, j)
+		}
+	}
+	return f.project(indexes), f.project([]int{f.schema.Label}), nil
+}
+
+// LabelLast returns a Frame with the label
+// as last column, as expected by NewLinearRegression,
+// NewkNN and ValidateRegression.
+func (f *Frame) LabelLast() (*Frame, error) {
+	if f.schema.Label < 0 {
+		return nil, errors.New("learn: no label column")
+	}
+	var indexes []int
+	for j := range f.columns {
+		if j != f.schema.Label {
+			indexes = append(indexes, j)
+		}
+	}
+	return f.project(append(indexes, f.schema.Label)), nil
+}
+
+func allColumns(n int) []int {
+	indexes := make([]int, n)
+	for j := range indexes {
+		indexes[j] = j
+	}
+	return indexes
+}
+
+// HConcat returns a Frame with the columns of tables
+// side by side. Tables must have the same number of rows,
+// the label is the one of the last table that has it.
+func HConcat(tables ...Table) (*Frame, error) {
+	if len(tables) == 0 {
+		return nil, ErrNoData
+	}
+	h := &hconcat{}
+	schema := Schema{Label: -1}
+	nRows, _ := tables[0].Caps()
+	for _, t := range tables {
+		n, _ := t.Caps()
+		if n != nRows {
+			return nil, fmt.Errorf("learn: concatenating tables with %d and %d rows", nRows, n)
+		}
+		s := NewFrame(t).Schema()
+		if s.Label >= 0 {
+			schema.Label = len(schema.Columns) + s.Label
+		}
+		schema.Columns = append(schema.Columns, s.Columns...)
+		h.tables = append(h.tables, t)
+		h.widths = append(h.widths, len(s.Columns))
+	}
+	h.rows = nRows
+	f := NewFrame(h)
+	f.schema = schema
+	return f, nil
+}
+
+// hconcat is a Table made of
+// columns of other Tables.
+type hconcat struct {
+	tables []Table
+	widths []int
+	rows   int
+}
+
+func (h *hconcat) Caps() (int, int) {
+	n := 0
+	for _, w := range h.widths {
+		n += w
+	}
+	return h.rows, n
+}
+
+func (h *hconcat) Row(i int) ([]interface{}, error) {
+	var row []interface{}
+	for k, t := range h.tables {
+		r, err := t.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		if len(r) != h.widths[k] {
+			return nil, ErrNoData
+		}
+		row = append(row, r...)
+	}
+	return row, nil
+}
+
+func (h *hconcat) Update(i int, r []interface{}) error {
+	start := 0
+	for k, t := range h.tables {
+		part := make([]interface{}, h.widths[k])
+		copy(part, r[start:start+h.widths[k]])
+		if err := t.Update(i, part); err != nil {
+			return err
+		}
+		start += h.widths[k]
+	}
+	return nil
+}
+
+// VConcat returns a Frame with the rows of tables
+// one after the other. Tables must have the same number
+// of columns, names and label are the ones of the first table.
+func VConcat(tables ...Table) (*Frame, error) {
+	if len(tables) == 0 {
+		return nil, ErrNoData
+	}
+	v := &vconcat{}
+	_, nColumns := tables[0].Caps()
+	for _, t := range tables {
+		n, c := t.Caps()
+		if c != nColumns {
+			return nil, fmt.Errorf("learn: concatenating tables with %d and %d columns", nColumns, c)
+		}
+		v.tables = append(v.tables, t)
+		v.offsets = append(v.offsets, v.rows)
+		v.rows += n
+	}
+	f := NewFrame(v)
+	f.schema = NewFrame(tables[0]).Schema()
+	return f, nil
+}
+
+// vconcat is a Table made of
+// rows of other Tables.
+type vconcat struct {
+	tables  []Table
+	offsets []int // Index of first row of each table.
+	rows    int
+}
+
+// locate returns the table holding
+// i-th row and its index there.
+func (v *vconcat) locate(i int) (Table, int, error) {
+	if i < 0 || i >= v.rows {
+		return nil, 0, ErrNoData
+	}
+	k := len(v.offsets) - 1
+	for v.offsets[k] > i {
+		k--
+	}
+	return v.tables[k], i - v.offsets[k], nil
+}
+
+func (v *vconcat) Caps() (int, int) {
+	_, nColumns := v.tables[0].Caps()
+	return v.rows, nColumns
+}
+
+func (v *vconcat) Row(i int) ([]interface{}, error) {
+	t, j, err := v.locate(i)
+	if err != nil {
+		return nil, err
+	}
+	return t.Row(j)
+}
+
+func (v *vconcat) Update(i int, r []interface{}) error {
+	t, j, err := v.locate(i)
+	if err != nil {
+		return err
+	}
+	return t.Update(j, r)
+}
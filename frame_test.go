@@ -0,0 +1,110 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+var frameData = MemoryTable{
+	{1.0, "a", 10.0},
+	{2.0, "b", 20.0},
+	{3.0, "a", 30.0},
+}
+
+func TestFrame_selectDrop(t *testing.T) {
+	f := NewFrame(frameData)
+	if names := f.Names(); !reflect.DeepEqual(names, []string{"c1", "c2", "c3"}) {
+		t.Fatalf("wrong names: %v", names)
+	}
+	s, err := f.Select("c3", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := s.Row(1)
+	if !reflect.DeepEqual(row, []interface{}{20.0, 2.0}) {
+		t.Errorf("wrong selected row: %v", row)
+	}
+	if s.Schema().Label != 0 {
+		t.Errorf("label at %d, want 0", s.Schema().Label)
+	}
+	d, err := f.Drop("c3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, c := d.Caps(); c != 2 || d.Schema().Label != -1 {
+		t.Errorf("got %d columns and label %d, want 2 and -1", c, d.Schema().Label)
+	}
+	if _, err := f.Select("c4"); err == nil {
+		t.Error("selecting unknown column must fail")
+	}
+}
+
+func TestFrame_filterUpdate(t *testing.T) {
+	data := cloneTable(frameData)
+	f := NewFrame(data)
+	a, err := f.Filter(func(row []interface{}) bool { return row[1] == "a" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ = a.Select("c1")
+	if r, c := a.Caps(); r != 2 || c != 1 {
+		t.Fatalf("got %dx%d, want 2x1", r, c)
+	}
+	col, err := a.Column(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(col, []interface{}{1.0, 3.0}) {
+		t.Errorf("wrong column: %v", col)
+	}
+	if err := a.Update(1, []interface{}{-3.0}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{-3.0, "a", 30.0}; !reflect.DeepEqual(data[2], want) {
+		t.Errorf("got %v, want %v", data[2], want)
+	}
+}
+
+func TestFrame_splitConcat(t *testing.T) {
+	f := NewFrame(frameData)
+	features, label, err := f.Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := HConcat(label, features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := h.Row(0)
+	if !reflect.DeepEqual(row, []interface{}{10.0, 1.0, "a"}) {
+		t.Errorf("wrong concatenated row: %v", row)
+	}
+	if h.Schema().Label != 0 {
+		t.Errorf("label at %d, want 0", h.Schema().Label)
+	}
+	last, err := h.LabelLast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := last.Names(); !reflect.DeepEqual(names, []string{"c1", "c2", "c3"}) {
+		t.Errorf("wrong names: %v", names)
+	}
+	v, err := VConcat(f, frameData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := v.Caps(); r != 6 {
+		t.Fatalf("got %d rows, want 6", r)
+	}
+	row, _ = v.Row(4)
+	if !reflect.DeepEqual(row, []interface{}{2.0, "b", 20.0}) {
+		t.Errorf("wrong row: %v", row)
+	}
+	if _, err := VConcat(f, features); err == nil {
+		t.Error("concatenating different columns must fail")
+	}
+}
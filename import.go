@@ -5,13 +5,20 @@
 package learn
 
 import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
 	"encoding/csv"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 type featureType uint8
@@ -32,126 +39,219 @@ const (
 //	------
 //	sigma
 //
-// If mu, sigma or catSet are nil
+// If mu, sigma or vocabularies are nil
 // they are calculated and returned
 // otherwise their computation is skipped
 // and passed values are used.
 //
 // Categorical features are mapped to
 // a representation suitable from
-// other functions in the package.
-func Normalize(data Table, mu, sigma []float64, catSet []string) ([]float64, []float64, []string, error) {
-	nRows, nColumns := data.Caps()
-	sum := make([]float64, nColumns)
-	if mu == nil || sigma == nil || catSet == nil {
-		catSet = make([]string, 0)
-		mapSet := make(map[string]struct{})
-		mu = make([]float64, nColumns)
-		sigma = make([]float64, nColumns)
-		for i := 0; i < nRows; i++ {
-			row, err := data.Row(i)
-			if err != nil {
-				return nil, nil, nil, err
-			}
-			for i, e := range row {
-				switch v := e.(type) {
-				case float64:
-					sum[i] += v
-				case string:
-					// NaN is used to indicate
-					// to not compute mean
-					// for the feature.
-					sum[i] = math.NaN()
-					mapSet[v] = struct{}{}
-				default:
-					return nil, nil, nil, unknownTypeErr(e)
-				}
-			}
-		}
-		// Compute means.
-		for i, s := range sum {
-			mu[i] = s
-			if !math.IsNaN(s) {
-				mu[i] = s / float64(nRows)
-			}
-		}
-		// Compute standard deviation.
-		for i := 0; i < nRows; i++ {
-			row, err := data.Row(i)
-			if err != nil {
-				return nil, nil, nil, err
-			}
-			for i, e := range row {
-				switch v := e.(type) {
-				case float64:
-					sigma[i] += math.Pow(v-mu[i], 2)
-				default:
-					// NaN is used to indicate
-					// to not compute sigma
-					// for the feature.
-					sigma[i] = math.NaN()
-				}
-			}
+// other functions in the package using
+// a Vocabulary for each column, nil for numerical ones.
+// Labels not in the passed Vocabularies,
+// like the ones first seen at prediction time,
+// become Categories with index -1.
+//
+// Columns with zero standard deviation, e.g. constant
+// ones, are only centered. Use a Scaler for other scalings.
+//
+// Missing values are ignored computing statistics
+// and left in place.
+//
+// If an error occurs data can be left partially
+// normalized, use NormalizeCopy to preserve it.
+func Normalize(data Table, mu, sigma []float64, vocabularies []Vocabulary) ([]float64, []float64, []Vocabulary, error) {
+	if mu == nil || sigma == nil || vocabularies == nil {
+		var err error
+		mu, sigma, vocabularies, err = normalizeStats(data)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-		for i, s := range sigma {
-			sigma[i] = s
-			if !math.IsNaN(s) {
-				sigma[i] = math.Sqrt(s / float64(nRows-1))
-			}
+	}
+	if err := applyNormalize(data, mu, sigma, vocabularies); err != nil {
+		return nil, nil, nil, err
+	}
+	return mu, sigma, vocabularies, nil
+}
+
+// normalizeStats computes means, standard deviations
+// and vocabularies of data's columns used by Normalize.
+// NaN mean and standard deviation mark categorical columns.
+func normalizeStats(data Table) (mu, sigma []float64, vocabularies []Vocabulary, err error) {
+	stats, err := ComputeStats(data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mu = make([]float64, len(stats))
+	sigma = make([]float64, len(stats))
+	vocabularies = make([]Vocabulary, len(stats))
+	for i, s := range stats {
+		if s.Categorical() {
+			mu[i], sigma[i] = math.NaN(), math.NaN()
+			vocabularies[i] = s.Vocabulary()
+			continue
 		}
-		// Order map set.
-		catSet = orderMapSet(mapSet)
+		mu[i], sigma[i] = s.Mean(), s.StdDev()
+	}
+	return mu, sigma, vocabularies, nil
+}
+
+// applyNormalize uses Table's Update()
+// to normalize data's rows with passed values.
+func applyNormalize(data Table, mu, sigma []float64, vocabularies []Vocabulary) error {
+	if len(mu) != len(sigma) {
+		return fmt.Errorf("learn: %d means but %d standard deviations", len(mu), len(sigma))
 	}
-	// Normalize.
+	nRows, _ := data.Caps()
 	for i := 0; i < nRows; i++ {
 		row, err := data.Row(i)
 		if err != nil {
-			return nil, nil, nil, err
+			return err
 		}
-		for i, e := range row {
-			switch v := e.(type) {
-			case float64:
+		// Test rows can miss the label
+		// if it is the last column.
+		if len(row) > len(mu) {
+			return rowWidthErr(i, len(row), len(mu))
+		}
+		if err := normalizeRow(row, mu, sigma, vocabularies); err != nil {
+			return err
+		}
+		if err := data.Update(i, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeRow normalizes row's elements in place.
+func normalizeRow(row []interface{}, mu, sigma []float64, vocabularies []Vocabulary) error {
+	for i, e := range row {
+		switch v := e.(type) {
+		case float64:
+			row[i] = v - mu[i]
+			// Columns with zero variance
+			// are only centered.
+			if sigma[i] != 0 && !math.IsNaN(sigma[i]) {
 				row[i] = (v - mu[i]) / sigma[i]
-			case string:
-				row[i] = newCategory(v, catSet)
-			default:
-				return nil, nil, nil, unknownTypeErr(e)
 			}
+		case string:
+			var vocabulary Vocabulary
+			if i < len(vocabularies) {
+				vocabulary = vocabularies[i]
+			}
+			row[i] = NewCategory(v, vocabulary)
+		case Missing:
+			// Left in place, use an Imputer
+			// to fill it.
+		default:
+			return unknownTypeErr(e)
+		}
+	}
+	return nil
+}
+
+// NormalizeCopy is like Normalize but leaves data
+// untouched and returns a normalized copy of it,
+// loaded in memory. If data is a SchemaTable,
+// so is the copy, with the same Schema.
+//
+// On error data is unchanged and no Table is returned.
+func NormalizeCopy(data Table, mu, sigma []float64, vocabularies []Vocabulary) (Table, []float64, []float64, []Vocabulary, error) {
+	if mu == nil || sigma == nil || vocabularies == nil {
+		var err error
+		mu, sigma, vocabularies, err = normalizeStats(data)
+		if err != nil {
+			return nil, nil, nil, nil, err
 		}
-		data.Update(i, row)
 	}
-	return mu, sigma, catSet, nil
+	out, err := copyTable(data)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := applyNormalize(out, mu, sigma, vocabularies); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return out, mu, sigma, vocabularies, nil
+}
+
+// copyTable returns a copy of data loaded
+// in memory, with data's Schema if any.
+func copyTable(data Table) (Table, error) {
+	nRows, _ := data.Caps()
+	out := make(MemoryTable, nRows)
+	for i := range out {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = copyRow(row)
+	}
+	if st, ok := data.(SchemaTable); ok {
+		return &schemaTable{
+			MemoryTable: out,
+			schema:      st.Schema(),
+		}, nil
+	}
+	return out, nil
+}
+
+// copyRow returns a copy of row
+// that does not share its categories.
+func copyRow(row []interface{}) []interface{} {
+	r := make([]interface{}, len(row))
+	for j, e := range row {
+		r[j] = cloneValue(e)
+	}
+	return r
 }
 
 // ReadAllCSV read whole file and load it
 // in memory.
-func ReadAllCSV(path string) (Table, error) {
-	// FIXME category must be exposed
-	// to let Table to be used externally.
+// Values equal to one of missing markers,
+// if any (e.g. "?" or "NA"), are stored as Missing.
+// Leading and trailing white spaces
+// of values are removed, use ReadCSV
+// to configure parsing.
+func ReadAllCSV(path string, missing ...string) (Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAllCSVFrom(f, missing...)
+}
+
+// ReadAllCSVFrom is like ReadAllCSV but reads
+// CSV data from r, that can be compressed
+// with gzip or bzip2.
+func ReadAllCSVFrom(r io.Reader, missing ...string) (Table, error) {
 	// FIXME is it possible to preallocate length
 	// having namers of file's rows?
 	var dataSlice MemoryTable = [][]interface{}{}
 	iRow := []interface{}{}
-	f, err := os.Open(path)
+	dr, err := decompress(r)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-	r := csv.NewReader(f)
+	defer dr.Close()
+	cr := csv.NewReader(dr)
 	for {
-		row, err := r.Read()
+		row, err := cr.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
 			return nil, err
 		}
-		// FIXME really needed?
-		cleanStrings(row)
+		cleanStrings(row, trimSpace)
 		iRow = make([]interface{}, len(row))
 		// Elements in rows
 		// are either float or string.
 		for i, e := range row {
+			if isMissingMarker(e, missing) {
+				iRow[i] = Missing{}
+				continue
+			}
 			switch kind(e) {
 			case floatFeature:
 				f, err := strconv.ParseFloat(e, 64)
@@ -172,6 +272,304 @@ func ReadAllCSV(path string) (Table, error) {
 	return dataSlice, nil
 }
 
+func isMissingMarker(s string, markers []string) bool {
+	for _, m := range markers {
+		if s == m {
+			return true
+		}
+	}
+	return false
+}
+
+// TrimMode selects how white spaces
+// are removed from CSV values.
+type TrimMode uint8
+
+const (
+	TrimSpace TrimMode = iota // Leading and trailing white spaces are removed.
+	TrimNone                  // Values are left untouched.
+	TrimAll                   // All white spaces are removed.
+)
+
+// CSVOptions configures ReadCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+	// Comment, if not zero, is the character
+	// that starts lines to be ignored.
+	Comment rune
+	// LazyQuotes allows quotes in unquoted fields
+	// and non doubled quotes in quoted fields.
+	LazyQuotes bool
+	// Trim selects white spaces removed from values.
+	Trim TrimMode
+	// Header indicates that the first record
+	// stores columns' names.
+	Header bool
+	// Schema, if not nil, sets names, types and label
+	// of columns. Types of AutoColumn columns are inferred,
+	// empty names are taken from header if present.
+	Schema *Schema
+	// Missing lists markers of missing values
+	// (e.g. "?" or "NA"), these are stored as Missing.
+	Missing []string
+}
+
+// ReadCSV reads whole file and loads it
+// in memory as a SchemaTable.
+//
+// Unlike ReadAllCSV, type of a column is inferred once
+// looking at all its values: a column is numeric
+// only if all its values are numbers, otherwise
+// all its values are stored as strings.
+// Types can be forced with opts.Schema, a *ParseError
+// is returned for values that do not match them.
+//
+// Values equal to one of opts.Missing markers
+// are stored as Missing and ignored inferring types.
+//
+// If opts.Schema is nil, last column is the label
+// and columns without a header are named
+// c1, c2, ... cn.
+func ReadCSV(path string, opts *CSVOptions) (SchemaTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSVFrom(f, opts)
+}
+
+// ReadCSVFrom is like ReadCSV but reads
+// CSV data from r, that can be compressed
+// with gzip or bzip2.
+func ReadCSVFrom(r io.Reader, opts *CSVOptions) (SchemaTable, error) {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+	d := newCSVDecoder(opts)
+	records, err := d.newReader(dr).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range records {
+		cleanStrings(row, d.clean)
+	}
+	// first is the number of
+	// the first data record in file.
+	first := 1
+	var header []string
+	if opts.Header {
+		if len(records) == 0 {
+			return nil, ErrNoData
+		}
+		header = records[0]
+		records = records[1:]
+		first++
+	}
+	for _, record := range records {
+		d.observe(record)
+	}
+	if err := d.makeSchema(opts.Schema, header); err != nil {
+		return nil, err
+	}
+	data := make(MemoryTable, len(records))
+	for i, record := range records {
+		data[i], err = d.decode(record, first+i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &schemaTable{
+		MemoryTable: data,
+		schema:      d.schema,
+	}, nil
+}
+
+// csvDecoder infers a Schema from CSV records
+// and converts them in Table's rows.
+type csvDecoder struct {
+	opts    CSVOptions
+	clean   func(string) string
+	missing map[string]struct{}
+	// inferred stores types inferred
+	// from observed records.
+	inferred []ColumnType
+	schema   Schema
+}
+
+func newCSVDecoder(opts *CSVOptions) *csvDecoder {
+	missing := make(map[string]struct{}, len(opts.Missing))
+	for _, m := range opts.Missing {
+		missing[m] = struct{}{}
+	}
+	return &csvDecoder{
+		opts:    *opts,
+		clean:   cleaner(opts.Trim),
+		missing: missing,
+	}
+}
+
+// newReader returns a csv.Reader
+// configured with decoder's options.
+func (d *csvDecoder) newReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	if d.opts.Comma != 0 {
+		cr.Comma = d.opts.Comma
+	}
+	cr.Comment = d.opts.Comment
+	cr.LazyQuotes = d.opts.LazyQuotes
+	return cr
+}
+
+// parseRecord parses and cleans a single record.
+// io.EOF is returned if b stores only a comment.
+func (d *csvDecoder) parseRecord(b []byte) ([]string, error) {
+	record, err := d.newReader(bytes.NewReader(b)).Read()
+	if err != nil {
+		return nil, err
+	}
+	cleanStrings(record, d.clean)
+	return record, nil
+}
+
+func (d *csvDecoder) isMissing(s string) bool {
+	_, ok := d.missing[s]
+	return ok
+}
+
+// observe updates inferred types with values of record,
+// ignoring missing ones.
+func (d *csvDecoder) observe(record []string) {
+	for len(d.inferred) < len(record) {
+		d.inferred = append(d.inferred, AutoColumn)
+	}
+	for j, e := range record {
+		if d.isMissing(e) || d.inferred[j] == CategoricalColumn {
+			continue
+		}
+		if kind(e) == floatFeature {
+			d.inferred[j] = NumericColumn
+		} else {
+			d.inferred[j] = CategoricalColumn
+		}
+	}
+}
+
+// makeSchema completes, or creates if s is nil,
+// the Schema used to decode records, using inferred types
+// for AutoColumn columns.
+func (d *csvDecoder) makeSchema(s *Schema, header []string) error {
+	var n int
+	switch {
+	case header != nil:
+		n = len(header)
+	case len(d.inferred) > 0:
+		n = len(d.inferred)
+	case s != nil:
+		n = len(s.Columns)
+	}
+	schema := Schema{
+		Columns: make([]Column, n),
+		Label:   n - 1,
+	}
+	if s != nil {
+		if len(s.Columns) != n {
+			return fmt.Errorf("learn: schema has %d columns, data has %d", len(s.Columns), n)
+		}
+		if s.Label < -1 || s.Label >= n {
+			return fmt.Errorf("learn: label column %d out of range", s.Label)
+		}
+		copy(schema.Columns, s.Columns)
+		schema.Label = s.Label
+	}
+	for j := range schema.Columns {
+		c := &schema.Columns[j]
+		if c.Name == "" {
+			if header != nil {
+				c.Name = header[j]
+			} else {
+				c.Name = "c" + strconv.Itoa(j+1)
+			}
+		}
+		if c.Type != AutoColumn {
+			continue
+		}
+		// Columns without values
+		// are categorical.
+		c.Type = CategoricalColumn
+		if j < len(d.inferred) && d.inferred[j] == NumericColumn {
+			c.Type = NumericColumn
+		}
+	}
+	d.schema = schema
+	return nil
+}
+
+// decode converts record, the n-th in file,
+// in a row with types from decoder's Schema.
+func (d *csvDecoder) decode(record []string, n int) ([]interface{}, error) {
+	if len(record) != len(d.schema.Columns) {
+		return nil, fmt.Errorf("learn: row %d has %d fields, expected %d", n, len(record), len(d.schema.Columns))
+	}
+	row := make([]interface{}, len(record))
+	for j, e := range record {
+		if d.isMissing(e) {
+			row[j] = Missing{}
+			continue
+		}
+		switch d.schema.Columns[j].Type {
+		case NumericColumn:
+			f, err := strconv.ParseFloat(e, 64)
+			if err != nil {
+				return nil, &ParseError{
+					Row:    n,
+					Column: j + 1,
+					Value:  e,
+					Type:   NumericColumn,
+				}
+			}
+			row[j] = f
+		default:
+			// This must be normalized
+			// with Normalize.
+			row[j] = e
+		}
+	}
+	return row, nil
+}
+
+// decompress returns a reader that decompresses r
+// if it starts with gzip or bzip2 magic numbers,
+// otherwise reads r as is.
+// Closing it does not close r.
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case isBzip2(magic):
+		return ioutil.NopCloser(bzip2.NewReader(br)), nil
+	}
+	return ioutil.NopCloser(br), nil
+}
+
+// isBzip2 reports whether magic is the header
+// of bzip2 data, "BZh" followed by block size.
+func isBzip2(magic []byte) bool {
+	return len(magic) == 4 && bytes.HasPrefix(magic, []byte("BZh")) &&
+		magic[3] >= '1' && magic[3] <= '9'
+}
+
 // kind identifies data type
 // from string for storing into a Go type.
 func kind(s string) featureType {
@@ -184,10 +582,38 @@ func kind(s string) featureType {
 	return stringFeature
 }
 
-func cleanStrings(row []string) {
-	cleanerRgxp := regexp.MustCompile(`[[:space:]]`)
+// spacesRgxp matches any white space.
+var spacesRgxp = regexp.MustCompile(`[[:space:]]`)
+
+func removeSpaces(s string) string {
+	return spacesRgxp.ReplaceAllString(s, "")
+}
+
+func trimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// cleaner returns the function that
+// removes white spaces from values as set by m.
+func cleaner(m TrimMode) func(string) string {
+	switch m {
+	case TrimNone:
+		return nil
+	case TrimAll:
+		return removeSpaces
+	default:
+		return trimSpace
+	}
+}
+
+// cleanStrings applies clean to every element of row,
+// nil clean leaves row untouched.
+func cleanStrings(row []string, clean func(string) string) {
+	if clean == nil {
+		return
+	}
 	for i, s := range row {
-		row[i] = cleanerRgxp.ReplaceAllString(s, "")
+		row[i] = clean(s)
 	}
 }
 
@@ -5,16 +5,19 @@
 package learn
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
 	"math"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestNormalize(t *testing.T) {
-	set := []string{
-		"foo",
-		"bar",
-		"crow",
-	}
+	set := NewVocabulary("foo", "bar", "crow")
 	var testCase MemoryTable = [][]interface{}{
 		{
 			400.31,
@@ -38,23 +41,26 @@ func TestNormalize(t *testing.T) {
 		{
 			0.665763742493870,
 			-0.270475058070629,
-			newCategory("foo", set),
+			NewCategory("foo", set),
 		},
 		{
 			0.484167074279229,
 			1.10741674719484,
-			newCategory("bar", set),
+			NewCategory("bar", set),
 		},
 		{
 			-1.14993081677310,
 			-0.836941689124212,
-			newCategory("crow", set),
+			NewCategory("crow", set),
 		},
 	}
-	mu, sigma, set, err := Normalize(testCase, nil, nil, nil)
+	mu, sigma, vocabularies, err := Normalize(testCase, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	if vocabularies[0] != nil || !reflect.DeepEqual(vocabularies[2], set) {
+		t.Errorf("wrong vocabularies: %v", vocabularies)
+	}
 	for i, row := range testCase {
 		for j, e := range row {
 			switch v := e.(type) {
@@ -63,6 +69,10 @@ func TestNormalize(t *testing.T) {
 				if !floatsAreEqual(v, g) {
 					t.Errorf("%10s: %v\n %10s: %+v", "expected", g, "got", v)
 				}
+			case *Category:
+				if g := expected[i][j].(*Category); !v.Equal(g) {
+					t.Errorf("%10s: %v\n %10s: %v", "expected", g, "got", v)
+				}
 			}
 		}
 	}
@@ -75,3 +85,266 @@ func TestNormalize(t *testing.T) {
 		}
 	}
 }
+
+// tempCSV writes content in a temporary file
+// returning its path.
+func tempCSV(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "learn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestReadCSV(t *testing.T) {
+	path := tempCSV(t, "zip,price,kind\n00501,10.5,flat\n10001,20,villa\n0x2A,15,flat\n")
+	defer os.Remove(path)
+	data, err := ReadCSV(path, &CSVOptions{Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Schema{
+		Columns: []Column{
+			{"zip", CategoricalColumn},
+			{"price", NumericColumn},
+			{"kind", CategoricalColumn},
+		},
+		Label: 2,
+	}
+	if !reflect.DeepEqual(data.Schema(), expected) {
+		t.Fatalf("expected schema: %+v, got: %+v", expected, data.Schema())
+	}
+	row, err := data.Row(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row[0] != "00501" || row[1] != 10.5 {
+		t.Fatalf("unexpected row: %v", row)
+	}
+	// Forcing a numeric zip code fails
+	// on third data record.
+	schema := &Schema{
+		Columns: []Column{{Type: NumericColumn}, {}, {}},
+		Label:   -1,
+	}
+	_, err = ReadCSV(path, &CSVOptions{Header: true, Schema: schema})
+	pErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("expected a *ParseError, got: %v", err)
+	}
+	if pErr.Row != 4 || pErr.Column != 1 {
+		t.Fatalf("expected error at row 4, column 1, got: %v", pErr)
+	}
+	for _, label := range []int{-7, 3} {
+		schema := &Schema{Columns: make([]Column, 3), Label: label}
+		if _, err := ReadCSV(path, &CSVOptions{Header: true, Schema: schema}); err == nil {
+			t.Errorf("expected an error for label %d", label)
+		}
+	}
+}
+
+func TestReadCSV_missing(t *testing.T) {
+	path := tempCSV(t, "1,?,x\n2,3,?\n?,4,y\n")
+	defer os.Remove(path)
+	data, err := ReadCSV(path, &CSVOptions{Missing: []string{"?"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, c := range data.Schema().Columns {
+		want := NumericColumn
+		if j == 2 {
+			want = CategoricalColumn
+		}
+		if c.Type != want {
+			t.Errorf("column %d: expected %s, got %s", j, want, c.Type)
+		}
+	}
+	row, err := data.Row(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row[1] != 3.0 || !IsMissing(row[2]) {
+		t.Fatalf("unexpected row: %v", row)
+	}
+}
+
+func TestReadAllCSV_missing(t *testing.T) {
+	input := "1, ?,x\nNA,3,y\n"
+	data, err := ReadAllCSVFrom(strings.NewReader(input), "?", "NA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range [][]interface{}{{1.0, Missing{}, "x"}, {Missing{}, 3.0, "y"}} {
+		row, _ := data.Row(i)
+		if !reflect.DeepEqual(row, expected) {
+			t.Errorf("expected row %v, got %v", expected, row)
+		}
+	}
+	// Without markers values are strings.
+	data, err = ReadAllCSVFrom(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row, _ := data.Row(0); row[1] != "?" {
+		t.Errorf("expected \"?\", got %v", row[1])
+	}
+}
+
+func TestReadCSV_options(t *testing.T) {
+	content := "# status;hours\n Never married ;  1\n\"Married; civ\";2\n"
+	path := tempCSV(t, content)
+	defer os.Remove(path)
+	cases := []struct {
+		trim          TrimMode
+		first, second interface{}
+	}{
+		{TrimSpace, "Never married", "Married; civ"},
+		{TrimNone, " Never married ", "Married; civ"},
+		{TrimAll, "Nevermarried", "Married;civ"},
+	}
+	for _, c := range cases {
+		opts := &CSVOptions{
+			Comma:   ';',
+			Comment: '#',
+			Trim:    c.trim,
+		}
+		data, err := ReadCSV(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := OpenCSV(path, opts, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tab := range []Table{data, file} {
+			if rows, columns := tab.Caps(); rows != 2 || columns != 2 {
+				t.Fatalf("expected 2 rows and 2 columns, got %d, %d", rows, columns)
+			}
+			row, err := tab.Row(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if row[0] != c.first {
+				t.Errorf("trim mode %d: expected %q, got %q", c.trim, c.first, row[0])
+			}
+			row, err = tab.Row(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if row[0] != c.second {
+				t.Errorf("trim mode %d: expected %q, got %q", c.trim, c.second, row[0])
+			}
+		}
+		file.Close()
+	}
+}
+
+func TestReadCSVFrom_compressed(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("1.5,a\n2,b\n"))
+	w.Close()
+	// Generated with python bz2.compress.
+	bz := "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x48\xd2\x04\x3b\x00\x00\x02\xd9\x00\x00\x10\x00\x05\x32\x00\x30\x00\x20\x00\x31\x0c\x08\x20\x09\xfe\xaa\x03\xa0\x8f\x8b\xb9\x22\x9c\x28\x48\x24\x69\x02\x1d\x80"
+	expected := MemoryTable{{1.5, "a"}, {2.0, "b"}}
+	readers := map[string]func() io.Reader{
+		"plain": func() io.Reader { return strings.NewReader("1.5,a\n2,b\n") },
+		"gzip":  func() io.Reader { return bytes.NewReader(gz.Bytes()) },
+		"bzip2": func() io.Reader { return strings.NewReader(bz) },
+	}
+	for name, r := range readers {
+		data, err := ReadCSVFrom(r(), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		all, err := ReadAllCSVFrom(r())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for i, e := range expected {
+			row, _ := data.Row(i)
+			allRow, _ := all.Row(i)
+			if !reflect.DeepEqual(row, e) || !reflect.DeepEqual(allRow, e) {
+				t.Errorf("%s: expected %v, got %v and %v", name, e, row, allRow)
+			}
+		}
+	}
+	// Plain text starting like bzip2 magic.
+	data, err := ReadAllCSVFrom(strings.NewReader("BZh,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row, _ := data.Row(0); !reflect.DeepEqual(row, []interface{}{"BZh", 1.0}) {
+		t.Errorf("unexpected row %v", row)
+	}
+}
+
+func TestNormalize_vocabularies(t *testing.T) {
+	var train MemoryTable = [][]interface{}{
+		{"Male", "Private"},
+		{"Female", "State-gov"},
+		{"Male", "Self-emp"},
+	}
+	_, _, vocabularies, err := Normalize(train, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vocabularies[0]) != 2 || len(vocabularies[1]) != 3 {
+		t.Fatalf("vocabularies are not per column: %v", vocabularies)
+	}
+	var test MemoryTable = [][]interface{}{
+		{"Male", "Never-worked"},
+	}
+	_, _, _, err = Normalize(test, []float64{0, 0}, []float64{1, 1}, vocabularies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unseen := test[0][1].(*Category)
+	if unseen.Index() != -1 {
+		t.Errorf("unseen category has index %d", unseen.Index())
+	}
+	d, err := elementsDistance(unseen, train[0][1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 1 {
+		t.Errorf("distance from unseen category is %f, want 1", d)
+	}
+	d, err = elementsDistance(test[0][0], train[0][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 0 {
+		t.Errorf("distance between same categories is %f, want 0", d)
+	}
+}
+
+func TestNormalizeCopy(t *testing.T) {
+	data := MemoryTable{{1.0, "a"}, {3.0, "b"}}
+	normalized, mu, _, _, err := NormalizeCopy(data, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0][0] != 1.0 || data[0][1] != "a" {
+		t.Errorf("source table modified: %v", data)
+	}
+	if mu[0] != 2 {
+		t.Errorf("expected mean 2, got %v", mu[0])
+	}
+	row, _ := normalized.Row(1)
+	if !floatsAreEqual(row[0].(float64), 1/math.Sqrt2) || row[1].(*Category).Label() != "b" {
+		t.Errorf("wrong normalized row: %v", row)
+	}
+	// Errors leave source untouched.
+	data = MemoryTable{{1.0, "a"}, {3.0, 1}}
+	if _, _, _, _, err := NormalizeCopy(data, []float64{0, 0}, []float64{1, 1}, []Vocabulary{nil, nil}); err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+	if data[0][0] != 1.0 || data[0][1] != "a" {
+		t.Errorf("source table modified on error: %v", data)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ImputeStrategy selects how
+// an Imputer fills missing values.
+type ImputeStrategy uint8
+
+const (
+	ImputeMean     ImputeStrategy = iota // Mean of the column, mode for categorical columns.
+	ImputeMedian                         // Median of the column, mode for categorical columns.
+	ImputeMode                           // Most frequent value of the column.
+	ImputeConstant                       // Imputer's Constant.
+	ImputeKNN                            // Mean, or mode, of the K nearest training samples.
+)
+
+// defaultImputeK is the number of neighbours
+// used by ImputeKNN if not specified.
+const defaultImputeK = 5
+
+// Imputer fills Missing values in Tables
+// with values learnt from training data.
+//
+// Numerical features are expected to be normalized
+// when ImputeKNN is used, otherwise features with larger
+// ranges dominate distance between samples.
+type Imputer struct {
+	Strategy ImputeStrategy
+	Constant interface{} // Value used by ImputeConstant.
+	K        int         // Neighbours used by ImputeKNN, 5 if <= 0.
+	fill     []interface{}
+	train    MemoryTable // Copy of training rows used by ImputeKNN.
+}
+
+// Fit computes values used to fill
+// missing values of data's columns.
+func (im *Imputer) Fit(data Table) error {
+	if im.Strategy == ImputeConstant {
+		if im.Constant == nil {
+			return errors.New("learn: no constant to impute")
+		}
+		return nil
+	}
+	nRows, nColumns := data.Caps()
+	numbers := make([][]float64, nColumns)
+	counts := make([]map[string]int, nColumns)
+	// values stores a value for each distinct
+	// label to be used as mode.
+	values := make([]map[string]interface{}, nColumns)
+	for j := range counts {
+		counts[j] = make(map[string]int)
+		values[j] = make(map[string]interface{})
+	}
+	im.train = nil
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		if im.Strategy == ImputeKNN {
+			// Snapshot the row, data can
+			// change after Fit.
+			r := make([]interface{}, len(row))
+			for j, e := range row {
+				r[j] = cloneValue(e)
+			}
+			im.train = append(im.train, r)
+		}
+		for j, e := range row {
+			var key string
+			switch v := e.(type) {
+			case float64:
+				numbers[j] = append(numbers[j], v)
+				key = fmt.Sprint(v)
+			case string:
+				key = v
+			case *Category:
+				key = v.label
+			case Missing:
+				continue
+			default:
+				return unknownTypeErr(e)
+			}
+			counts[j][key]++
+			values[j][key] = e
+		}
+	}
+	im.fill = make([]interface{}, nColumns)
+	for j := range im.fill {
+		numerical := len(numbers[j]) > 0 && len(numbers[j]) == sumCounts(counts[j])
+		switch {
+		case len(counts[j]) == 0:
+			// Only missing values,
+			// nothing to impute.
+			im.fill[j] = Missing{}
+		case numerical && (im.Strategy == ImputeMean || im.Strategy == ImputeKNN):
+			var s float64
+			for _, f := range numbers[j] {
+				s += f
+			}
+			im.fill[j] = s / float64(len(numbers[j]))
+		case numerical && im.Strategy == ImputeMedian:
+			sort.Float64s(numbers[j])
+			im.fill[j] = quantile(numbers[j], 0.5)
+		default:
+			im.fill[j] = values[j][mode(counts[j])]
+		}
+	}
+	return nil
+}
+
+// Transform uses Table's Update()
+// to fill missing values of data
+// and returns it.
+// Fit must be called before.
+func (im *Imputer) Transform(data Table) (Table, error) {
+	if im.fill == nil && im.Strategy != ImputeConstant {
+		return nil, errors.New("learn: Imputer is not fitted")
+	}
+	nRows, _ := data.Caps()
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		for j, e := range row {
+			if !IsMissing(e) {
+				continue
+			}
+			var v interface{}
+			switch im.Strategy {
+			case ImputeConstant:
+				v = im.Constant
+			case ImputeKNN:
+				v, err = im.nearestValue(row, j)
+				if err != nil {
+					return nil, err
+				}
+			default:
+				if j < len(im.fill) {
+					v = im.fill[j]
+				}
+			}
+			if v == nil {
+				return nil, fmt.Errorf("learn: no value to impute in column %d", j)
+			}
+			row[j] = cloneValue(v)
+			changed = true
+		}
+		if changed {
+			if err := data.Update(i, row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return data, nil
+}
+
+// nearestValue returns the mean, or mode,
+// of column j among the K training samples nearest to row
+// that have a value for j.
+func (im *Imputer) nearestValue(row []interface{}, j int) (interface{}, error) {
+	k := im.K
+	if k <= 0 {
+		k = defaultImputeK
+	}
+	samples := newKSamples(k)
+	for _, trainRow := range im.train {
+		if j >= len(trainRow) || IsMissing(trainRow[j]) {
+			continue
+		}
+		d, err := imputeDistance(row, trainRow)
+		if err != nil {
+			return nil, err
+		}
+		samples.checkUpdate(d, trainRow)
+	}
+	var sum float64
+	n := 0
+	counts := make(map[string]int)
+	values := make(map[string]interface{})
+	for _, s := range samples {
+		if s.row == nil {
+			continue
+		}
+		switch v := s.row[j].(type) {
+		case float64:
+			sum += v
+			n++
+		case string:
+			counts[v]++
+			values[v] = v
+		case *Category:
+			counts[v.label]++
+			values[v.label] = v
+		}
+	}
+	switch {
+	case n > 0:
+		return sum / float64(n), nil
+	case len(counts) > 0:
+		return values[mode(counts)], nil
+	}
+	// No neighbours, fall back
+	// to column's mean or mode.
+	return im.fill[j], nil
+}
+
+// imputeDistance returns mean distance
+// between elements of a and b that
+// are both not missing.
+// Strings are compared by equality.
+func imputeDistance(a, b []interface{}) (float64, error) {
+	var total float64
+	n := 0
+	for i, e := range a {
+		if i >= len(b) || IsMissing(e) || IsMissing(b[i]) {
+			continue
+		}
+		if s, ok := e.(string); ok {
+			if s != b[i] {
+				total++
+			}
+			n++
+			continue
+		}
+		d, err := elementsDistance(e, b[i])
+		if err != nil {
+			return 0, err
+		}
+		total += d
+		n++
+	}
+	if n == 0 {
+		// Nothing in common,
+		// maximum distance.
+		return 1, nil
+	}
+	return total / float64(n), nil
+}
+
+// mode returns the most frequent key,
+// the smallest one in case of ties.
+func mode(counts map[string]int) string {
+	var best string
+	max := -1
+	for k, c := range counts {
+		if c > max || (c == max && k < best) {
+			best = k
+			max = c
+		}
+	}
+	return best
+}
+
+func sumCounts(counts map[string]int) int {
+	var n int
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
+
+// cloneValue copies categories so that
+// imputed rows do not share them.
+func cloneValue(v interface{}) interface{} {
+	if c, ok := v.(*Category); ok {
+		cc := *c
+		return &cc
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+var missingData = MemoryTable{
+	{1.0, "a", 10.0},
+	{2.0, "b", Missing{}},
+	{Missing{}, "a", 30.0},
+	{9.0, Missing{}, 40.0},
+}
+
+func TestImputer(t *testing.T) {
+	cases := []struct {
+		imputer  Imputer
+		expected []interface{}
+	}{
+		// Missing values of first,
+		// second and third column.
+		{Imputer{Strategy: ImputeMean}, []interface{}{4.0, "a", 80.0 / 3}},
+		{Imputer{Strategy: ImputeMedian}, []interface{}{2.0, "a", 30.0}},
+		{Imputer{Strategy: ImputeMode}, []interface{}{1.0, "a", 10.0}},
+		{Imputer{Strategy: ImputeConstant, Constant: 0.0}, []interface{}{0.0, 0.0, 0.0}},
+	}
+	for i, c := range cases {
+		data := cloneTable(missingData)
+		if err := c.imputer.Fit(data); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.imputer.Transform(data); err != nil {
+			t.Fatal(err)
+		}
+		got := []interface{}{data[2][0], data[3][1], data[1][2]}
+		if !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("case %d: expected %v, got %v", i, c.expected, got)
+		}
+	}
+}
+
+func TestImputer_KNN(t *testing.T) {
+	data := cloneTable(missingData)
+	im := Imputer{Strategy: ImputeKNN, K: 1}
+	if err := im.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := im.Transform(data); err != nil {
+		t.Fatal(err)
+	}
+	// Row 1 is nearest to row 0, row 2 to
+	// row 1 as values imputed by Transform
+	// are not used as training samples.
+	got := []interface{}{data[1][2], data[2][0]}
+	expected := []interface{}{10.0, 2.0}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	// Changes to training data
+	// after Fit are not seen.
+	train := cloneTable(missingData)
+	if err := im.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	train[0][2] = 99.0
+	data = cloneTable(missingData)
+	if _, err := im.Transform(data); err != nil {
+		t.Fatal(err)
+	}
+	if data[1][2] != 10.0 {
+		t.Errorf("expected 10, got %v", data[1][2])
+	}
+}
+
+func TestNormalize_missing(t *testing.T) {
+	data := cloneTable(missingData)
+	mu, _, _, err := Normalize(data, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsAreEqual(mu[0], 4) {
+		t.Errorf("expected mean 4, got %v", mu[0])
+	}
+	if !IsMissing(data[2][0]) || !IsMissing(data[3][1]) {
+		t.Errorf("missing values must be left in place: %v", data)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ReadJSONLines reads JSON Lines data, one JSON object per line,
+// and loads it in memory as a SchemaTable whose columns
+// are objects' fields.
+//
+// schema maps fields to columns by name, fields not in schema
+// are ignored. Numbers are stored in numeric columns, strings and
+// booleans in categorical ones, null and absent fields as Missing.
+// Types of AutoColumn columns are inferred as in ReadCSV,
+// a *ParseError is returned for values that do not match them.
+//
+// If schema is nil, columns are all the fields found,
+// sorted by name, and there is no label.
+func ReadJSONLines(r io.Reader, schema *Schema) (SchemaTable, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+	var objects []map[string]interface{}
+	fields := make(map[string]struct{})
+	s := bufio.NewScanner(dr)
+	// Lines can be longer than default.
+	s.Buffer(nil, 1<<24)
+	for n := 1; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			objects = append(objects, nil)
+			continue
+		}
+		var o map[string]interface{}
+		if err := json.Unmarshal(line, &o); err != nil {
+			return nil, fmt.Errorf("learn: line %d: %v", n, err)
+		}
+		for k := range o {
+			fields[k] = struct{}{}
+		}
+		objects = append(objects, o)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	var sc Schema
+	if schema != nil {
+		sc.Columns = make([]Column, len(schema.Columns))
+		copy(sc.Columns, schema.Columns)
+		sc.Label = schema.Label
+	} else {
+		for _, name := range orderMapSet(fields) {
+			sc.Columns = append(sc.Columns, Column{Name: name})
+		}
+		sc.Label = -1
+	}
+	// Infer types.
+	for j := range sc.Columns {
+		c := &sc.Columns[j]
+		if c.Type != AutoColumn {
+			continue
+		}
+		c.Type = CategoricalColumn
+		for _, o := range objects {
+			switch o[c.Name].(type) {
+			case float64:
+				c.Type = NumericColumn
+				continue
+			case nil:
+				continue
+			}
+			c.Type = CategoricalColumn
+			break
+		}
+	}
+	var data MemoryTable
+	for i, o := range objects {
+		if o == nil {
+			// Empty line.
+			continue
+		}
+		row := make([]interface{}, len(sc.Columns))
+		for j, c := range sc.Columns {
+			v, err := jsonValue(o[c.Name], c.Type)
+			if err != nil {
+				return nil, &ParseError{Row: i + 1, Column: j + 1, Value: fmt.Sprint(o[c.Name]), Type: c.Type}
+			}
+			row[j] = v
+		}
+		data = append(data, row)
+	}
+	return &schemaTable{
+		MemoryTable: data,
+		schema:      sc,
+	}, nil
+}
+
+// jsonValue converts a decoded JSON value
+// in a row's element of type t.
+func jsonValue(v interface{}, t ColumnType) (interface{}, error) {
+	switch e := v.(type) {
+	case nil:
+		return Missing{}, nil
+	case float64:
+		if t == NumericColumn {
+			return e, nil
+		}
+		return strconv.FormatFloat(e, 'g', -1, 64), nil
+	case string:
+		if t == CategoricalColumn {
+			return e, nil
+		}
+	case bool:
+		if t == CategoricalColumn {
+			return strconv.FormatBool(e), nil
+		}
+	}
+	return nil, unknownTypeErr(v)
+}
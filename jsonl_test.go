@@ -0,0 +1,60 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const eventsJSONL = `{"user": "a", "duration": 1.5, "mobile": true}
+{"user": "b", "duration": null}
+
+{"user": "c", "duration": 3, "extra": {"nested": 1}}
+`
+
+func TestReadJSONLines(t *testing.T) {
+	schema := &Schema{
+		Columns: []Column{
+			{Name: "duration"},
+			{Name: "mobile"},
+			{Name: "user", Type: CategoricalColumn},
+		},
+		Label: 2,
+	}
+	data, err := ReadJSONLines(strings.NewReader(eventsJSONL), schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MemoryTable{
+		{1.5, "true", "a"},
+		{Missing{}, Missing{}, "b"},
+		{3.0, Missing{}, "c"},
+	}
+	if rows, _ := data.Caps(); rows != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), rows)
+	}
+	for i, e := range expected {
+		row, _ := data.Row(i)
+		if !reflect.DeepEqual(row, e) {
+			t.Errorf("row %d: expected %v, got %v", i, e, row)
+		}
+	}
+	if c := data.Schema().Columns; c[0].Type != NumericColumn || c[1].Type != CategoricalColumn {
+		t.Errorf("unexpected types: %v", c)
+	}
+	// Nested objects are not supported.
+	_, err = ReadJSONLines(strings.NewReader(eventsJSONL), nil)
+	if _, ok := err.(*ParseError); !ok {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+	// Strings are not numbers.
+	schema.Columns[2].Type = NumericColumn
+	_, err = ReadJSONLines(strings.NewReader(eventsJSONL), schema)
+	if _, ok := err.(*ParseError); !ok {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+}
@@ -7,37 +7,79 @@ package learn
 import (
 	"math"
 	"math/rand"
-	"strconv"
-	"strings"
 	"time"
 )
 
-// BUG(eraclitux): randomly returns same
-// category in tests.
-func createRandCategory(l uint) *category {
-	sS := []string{}
-	for i := 0; i < int(l); i++ {
-		sN := strconv.Itoa(rand.Intn(2))
-		sS = append(sS, sN)
-	}
-	return newCategory(strings.Join(sS, ","), nil) // FIXME
+// createRandCategory returns a copy of one of categories
+// chosen at random, sample if there are none.
+func createRandCategory(categories []*Category, sample *Category) *Category {
+	c := *sample
+	if len(categories) > 0 {
+		c = *categories[rand.Intn(len(categories))]
+	}
+	return &c
+}
+
+// columnSamples returns, for each column of data,
+// its first non missing value, Missing if there is none,
+// and the categories of its vocabulary seen in data.
+func columnSamples(data Table) ([]interface{}, [][]*Category, error) {
+	nRows, nColumns := data.Caps()
+	samples := make([]interface{}, nColumns)
+	for j := range samples {
+		samples[j] = Missing{}
+	}
+	categories := make([][]*Category, nColumns)
+	seen := make([]map[int]bool, nColumns)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j, e := range row {
+			if j >= nColumns || IsMissing(e) {
+				continue
+			}
+			if IsMissing(samples[j]) {
+				samples[j] = e
+			}
+			c, ok := e.(*Category)
+			if !ok || c.unseen() {
+				continue
+			}
+			if seen[j] == nil {
+				seen[j] = make(map[int]bool)
+			}
+			if !seen[j][c.Index()] {
+				seen[j][c.Index()] = true
+				categories[j] = append(categories[j], c)
+			}
+		}
+	}
+	return samples, categories, nil
 }
 
 // FIXME Andrew Ng suggests to initialize centroids
 // to points of training samples.
-func createRandomCentroids(k int, s []interface{}) ([][]interface{}, error) {
-	l := len(s)
+func createRandomCentroids(k int, data Table) ([][]interface{}, error) {
+	samples, categories, err := columnSamples(data)
+	if err != nil {
+		return nil, err
+	}
 	r := make([][]interface{}, 0, k)
 	for i := 0; i < k; i++ {
-		c := make([]interface{}, l)
-		for i, e := range s {
+		c := make([]interface{}, len(samples))
+		for j, e := range samples {
 			switch e.(type) {
 			case float64:
-				c[i] = rand.Float64()
-			case *category:
-				c[i] = createRandCategory(e.(*category).catNumber)
+				c[j] = rand.Float64()
+			case *Category:
+				c[j] = createRandCategory(categories[j], e.(*Category))
 			case string:
-				c[i] = ""
+				c[j] = ""
+			case Missing:
+				// No values in column.
+				c[j] = Missing{}
 			default:
 				return nil, unknownTypeErr(e)
 			}
@@ -52,10 +94,10 @@ func zeroCentroid(c []interface{}) {
 		switch e.(type) {
 		case float64:
 			c[i] = float64(0)
-		case *category:
-			c[i].(*category).zero()
-		case string:
-			// do nothing for string features.
+		case *Category:
+			c[i].(*Category).zero()
+		case string, Missing:
+			// do nothing for string or empty features.
 		default:
 			panic("unknown type zeroing centroid")
 		}
@@ -64,30 +106,43 @@ func zeroCentroid(c []interface{}) {
 
 // incrementCentroid adds quantities to centroids elements
 // to calculate the mean after.
-func incrementCentroid(c []interface{}, d []interface{}) {
+// Missing elements in d are skipped, counts
+// stores the number of non missing ones per feature.
+func incrementCentroid(c []interface{}, d []interface{}, counts []int) {
 	for i, e := range c {
+		if IsMissing(d[i]) {
+			continue
+		}
 		switch e.(type) {
 		case float64:
 			c[i] = e.(float64) + d[i].(float64)
-		case *category:
-			e.(*category).add(d[i].(*category))
-		case string:
-			// do nothing for string features.
+		case *Category:
+			e.(*Category).add(d[i].(*Category))
+		case string, Missing:
+			// do nothing for string or empty features.
+			continue
 		default:
 			panic("unknown type increasing centroid")
 		}
+		counts[i]++
 	}
 }
 
-func centerCentroid(c []interface{}, l int) {
+// centerCentroid divides each feature
+// by its number of non missing elements.
+// Features without elements are left untouched.
+func centerCentroid(c []interface{}, counts []int) {
 	for i, e := range c {
+		if counts[i] == 0 {
+			continue
+		}
 		switch e.(type) {
 		case float64:
-			c[i] = e.(float64) / float64(l)
-		case *category:
-			e.(*category).mean(l)
-		case string:
-			// do nothing for string features.
+			c[i] = e.(float64) / float64(counts[i])
+		case *Category:
+			e.(*Category).mean(counts[i])
+		case string, Missing:
+			// do nothing for string or empty features.
 		default:
 			panic("unknown type centering centroid")
 		}
@@ -101,7 +156,10 @@ func moveCentroids(centroids [][]interface{}, dataMap []Point, data Table) error
 	for _, p := range dataMap {
 		eleMap[p.K]++
 	}
+	// Non missing elements of each feature of a centroid.
+	counts := make([][]int, len(centroids))
 	for k := 0; k < len(centroids); k++ {
+		counts[k] = make([]int, len(centroids[k]))
 		if eleMap[k] == 0 {
 			continue
 		}
@@ -112,13 +170,13 @@ func moveCentroids(centroids [][]interface{}, dataMap []Point, data Table) error
 		if err != nil {
 			return err
 		}
-		incrementCentroid(centroids[p.K], row)
+		incrementCentroid(centroids[p.K], row, counts[p.K])
 	}
 	for k := 0; k < len(centroids); k++ {
 		if eleMap[k] == 0 {
 			continue
 		}
-		centerCentroid(centroids[k], eleMap[k])
+		centerCentroid(centroids[k], counts[k])
 	}
 	return nil
 }
@@ -137,13 +195,7 @@ func Kmc(data Table, k int, weights []float64) (result *KmcResult, er error) {
 	for i := 0; i < nRows; i++ {
 		dataMap[i].Distance = 1
 	}
-	centroids := make([][]interface{}, 0)
-	row, er := data.Row(0)
-	if er != nil {
-		return
-
-	}
-	centroids, er = createRandomCentroids(k, row)
+	centroids, er := createRandomCentroids(k, data)
 	if er != nil {
 		return
 	}
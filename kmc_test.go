@@ -13,12 +13,12 @@ func TestZeroCentroid(t *testing.T) {
 	toZero := []interface{}{
 		float64(0.9),
 		float64(0.3),
-		newCategory("foo", nil),
+		NewCategory("foo", nil),
 	}
 	zero := []interface{}{
 		0.0,
 		0.0,
-		&category{},
+		&Category{},
 	}
 	zeroCentroid(toZero)
 	if !reflect.DeepEqual(toZero, zero) {
@@ -30,22 +30,22 @@ func TestIncrementCentroid(t *testing.T) {
 	incrementing := []interface{}{
 		float64(1),
 		float64(0.5),
-		newCategory("0,1,1,1", nil),
+		NewCategory("0,1,1,1", nil),
 	}
 	increment := []interface{}{
 		float64(1),
 		float64(0.5),
-		newCategory("0,0,0,1", nil),
+		NewCategory("0,0,0,1", nil),
 	}
 	expected := []interface{}{
 		float64(2),
 		float64(1),
-		newCategory("1,0,0,0", nil),
+		NewCategory("1,0,0,0", nil),
 	}
 	if testing.Verbose() {
 		t.Logf("incrementing: %v, increment: %v\n", incrementing, increment)
 	}
-	incrementCentroid(incrementing, increment)
+	incrementCentroid(incrementing, increment, make([]int, 3))
 	if !reflect.DeepEqual(incrementing, expected) {
 		t.Fatalf("wrongly incremented, expected: %v got: %v", expected, incrementing)
 	}
@@ -58,33 +58,37 @@ func TestCenterCentroid(t *testing.T) {
 	incrementing := []interface{}{
 		float64(1),
 		float64(0.5),
-		newCategory("0,0,0,1", nil),
+		NewCategory("0,0,0,1", nil),
 	}
 	increment := []interface{}{
 		float64(3),
 		float64(1.5),
-		newCategory("0,0,1,1", nil),
+		NewCategory("0,0,1,1", nil),
 	}
 	expected := []interface{}{
 		float64(2),
 		float64(1),
-		newCategory("0,0,1,0", nil),
+		NewCategory("0,0,1,0", nil),
 	}
-	incrementCentroid(incrementing, increment)
-	centerCentroid(incrementing, 2)
+	counts := []int{1, 1, 1}
+	incrementCentroid(incrementing, increment, counts)
+	centerCentroid(incrementing, counts)
 	if !reflect.DeepEqual(incrementing, expected) {
 		t.Fatalf("wrongly incremented, expected: %v got: %v", expected, incrementing)
 	}
 }
 
 func TestCategory_Distance(t *testing.T) {
+	v := NewVocabulary("a", "b", "c", "d", "e", "f", "g", "h")
 	cases := []struct {
-		a, b     *category
+		a, b     *Category
 		distance float64
 	}{
-		{newCategory("1,1,1,1", nil), newCategory("0,0,0,0", nil), 1},
-		{newCategory("0,1,0,1", nil), newCategory("1,0,1,0", nil), 1},
-		{newCategory("0,0,1,1", nil), newCategory("0,0,0,0", nil), 0.5},
+		{NewCategory("a", v), NewCategory("a", v), 0},
+		{NewCategory("a", v), NewCategory("b", v), 1},
+		{NewCategory("a", v), NewCategory("h", v), 1},
+		{NewCategory("a", v), NewCategory("x", v), 1},
+		{NewCategory("x", v), NewCategory("x", v), 0},
 	}
 	for i, c := range cases {
 		d := c.a.distance(c.b)
@@ -96,24 +100,24 @@ func TestCategory_Distance(t *testing.T) {
 
 func TestCategory_Mean(t *testing.T) {
 	cases := []struct {
-		a    *category
-		cats []*category
-		mean *category
+		a    *Category
+		cats []*Category
+		mean *Category
 	}{
 		{
-			newCategory("0,0,0,0", nil),
-			[]*category{newCategory("1,1,1,1", nil), newCategory("1,1,1,1", nil)},
-			newCategory("1,1,1,1", nil),
+			NewCategory("0,0,0,0", nil),
+			[]*Category{NewCategory("1,1,1,1", nil), NewCategory("1,1,1,1", nil)},
+			NewCategory("1,1,1,1", nil),
 		},
 		{
-			newCategory("0,0,0,0", nil),
-			[]*category{newCategory("0,0,1,1", nil), newCategory("1,1,0,0", nil), newCategory("1,1,0,0", nil)},
-			newCategory("1,0,0,1", nil),
+			NewCategory("0,0,0,0", nil),
+			[]*Category{NewCategory("0,0,1,1", nil), NewCategory("1,1,0,0", nil), NewCategory("1,1,0,0", nil)},
+			NewCategory("1,0,0,1", nil),
 		},
 		{
-			newCategory("0,0,0,0", nil),
-			[]*category{newCategory("0,0,1,1", nil), newCategory("1,1,0,0", nil), newCategory("1,1,0,0", nil), newCategory("1,1,0,0", nil)},
-			newCategory("1,0,0,1", nil),
+			NewCategory("0,0,0,0", nil),
+			[]*Category{NewCategory("0,0,1,1", nil), NewCategory("1,1,0,0", nil), NewCategory("1,1,0,0", nil), NewCategory("1,1,0,0", nil)},
+			NewCategory("1,0,0,1", nil),
 		},
 	}
 	for i, c := range cases {
@@ -128,23 +132,37 @@ func TestCategory_Mean(t *testing.T) {
 }
 
 func TestCreateRandCategory(t *testing.T) {
-	a := createRandCategory(4)
-	b := createRandCategory(4)
-	if reflect.DeepEqual(a, b) {
-		t.Fatal("equal!")
+	v := NewVocabulary("a", "b", "c", "d")
+	var categories []*Category
+	for _, l := range v {
+		categories = append(categories, NewCategory(l, v))
+	}
+	labels := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		c := createRandCategory(categories, categories[0])
+		if c.Index() < 0 {
+			t.Fatalf("unseen random category %v", c)
+		}
+		if c == categories[c.Index()] {
+			t.Fatal("random category is not a copy")
+		}
+		labels[c.Label()] = true
+	}
+	if len(labels) < 2 {
+		t.Errorf("always the same category: %v", labels)
 	}
 }
 
 func TestCreateRandomCentroids(t *testing.T) {
 	f := []interface{}{
 		float64(1),
-		newCategory("[1,0,0,0]", nil),
+		NewCategory("[1,0,0,0]", nil),
 	}
-	a, err := createRandomCentroids(4, f)
+	a, err := createRandomCentroids(4, MemoryTable{f})
 	if err != nil {
 		t.Fatal(err)
 	}
-	b, err := createRandomCentroids(4, f)
+	b, err := createRandomCentroids(4, MemoryTable{f})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -153,6 +171,40 @@ func TestCreateRandomCentroids(t *testing.T) {
 	}
 }
 
+func TestCreateRandomCentroids_missing(t *testing.T) {
+	data := MemoryTable{
+		{Missing{}, Missing{}, Missing{}},
+		{2.0, NewCategory("a", Vocabulary{"a", "b"}), Missing{}},
+	}
+	c, err := createRandomCentroids(2, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range c {
+		if _, ok := e[0].(float64); !ok {
+			t.Errorf("expected numeric feature, got %v", e[0])
+		}
+		if _, ok := e[1].(*Category); !ok {
+			t.Errorf("expected categorical feature, got %v", e[1])
+		}
+		if !IsMissing(e[2]) {
+			t.Errorf("expected Missing feature, got %v", e[2])
+		}
+	}
+}
+
+func TestCenterCentroid_missing(t *testing.T) {
+	c := []interface{}{0.0, 0.0}
+	counts := make([]int, 2)
+	for _, d := range [][]interface{}{{1.0, Missing{}}, {3.0, 4.0}} {
+		incrementCentroid(c, d, counts)
+	}
+	centerCentroid(c, counts)
+	if expected := []interface{}{2.0, 4.0}; !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected %v, got %v", expected, c)
+	}
+}
+
 func TestKmc(t *testing.T) {
 	data, err := ReadAllCSV("datasets/iris.csv")
 	if err != nil {
@@ -155,7 +155,7 @@ func (t kSamples) getNearest() string {
 	for _, e := range t {
 		// get label as last column in row.
 		// FIXME check this assertion
-		tmp := e.row[len(e.row)-1].(*category)
+		tmp := e.row[len(e.row)-1].(*Category)
 		label := tmp.label
 		if _, ok := m[label]; ok {
 			m[label]++
@@ -189,12 +189,14 @@ func makeKDTreePoint(row []interface{}) *kdTreePoint {
 		switch v := e.(type) {
 		case float64:
 			features = append(features, v)
-		case *category:
+		case *Category:
 			if i == len(row)-1 {
 				label = v.label
 				continue
 			}
 			features = append(features, v)
+		case Missing:
+			features = append(features, v)
 		}
 	}
 	return &kdTreePoint{
@@ -212,8 +214,11 @@ func (p *kdTreePoint) GetValue(i int) float64 {
 	switch v := p.features[i].(type) {
 	case float64:
 		f = v
-	case *category:
+	case *Category:
 		f = float64(v.data)
+	case Missing:
+		// Mean of normalized features.
+		f = 0
 	}
 	return f
 }
@@ -50,15 +50,15 @@ func TestKSamples_CheckUpdate(t *testing.T) {
 
 func TestKSamples_GetNearest(t *testing.T) {
 	samples := newKSamples(5)
-	row := []interface{}{0.2, 0.4, 0.1, newCategory("one", nil)}
+	row := []interface{}{0.2, 0.4, 0.1, NewCategory("one", nil)}
 	samples.checkUpdate(0.5, row)
-	row = []interface{}{0.1, 0.3, 0.0, newCategory("one", nil)}
+	row = []interface{}{0.1, 0.3, 0.0, NewCategory("one", nil)}
 	samples.checkUpdate(0.5, row)
-	row = []interface{}{0.1, 0.3, 0.0, newCategory("two", nil)}
+	row = []interface{}{0.1, 0.3, 0.0, NewCategory("two", nil)}
 	samples.checkUpdate(0.4, row)
-	row = []interface{}{0.14, 0.33, 0.23, newCategory("two", nil)}
+	row = []interface{}{0.14, 0.33, 0.23, NewCategory("two", nil)}
 	samples.checkUpdate(0.6, row)
-	row = []interface{}{0.13, 0.33, 0.23, newCategory("two", nil)}
+	row = []interface{}{0.13, 0.33, 0.23, NewCategory("two", nil)}
 	samples.checkUpdate(0.6, row)
 
 	nearest := samples.getNearest()
@@ -67,7 +67,7 @@ func TestKSamples_GetNearest(t *testing.T) {
 	}
 }
 
-func loadTrainSet(t *testing.T, set string) (Table, []float64, []float64, []string) {
+func loadTrainSet(t *testing.T, set string) (Table, []float64, []float64, []Vocabulary) {
 	path := fmt.Sprintf("datasets/%s.csv", set)
 	trainSet, err := ReadAllCSV(path)
 	if err != nil {
@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+
+	"github.com/gonum/matrix"
+	"github.com/gonum/matrix/mat64"
+)
+
+// epsilon64 is the machine epsilon
+// for float64.
+const epsilon64 = 2.220446049250313e-16
+
+// leastSquares returns theta that minimizes
+// the euclidean norm of X*theta - Y.
+//
+// SVD is computed on X directly,
+// without forming X'*X that squares
+// its condition number.
+//
+// Singular values smaller than rcond times the
+// largest singular value are considered zero.
+// If rcond <= 0 a default tolerance
+// that accounts for rounding errors is used.
+func leastSquares(X, Y *mat64.Dense, rcond float64) (*mat64.Dense, error) {
+	r, c := X.Dims()
+	svd := new(mat64.SVD)
+	ok := svd.Factorize(X, matrix.SVDThin)
+	if !ok {
+		return nil, errors.New("learn: not factorizable")
+	}
+	singValues := svd.Values(nil)
+	cutoff := svdCutoff(singValues, r, c, rcond)
+	var U, V mat64.Dense
+	U.UFromSVD(svd)
+	V.VFromSVD(svd)
+	// theta = V * pinv(Σ) * U' * Y
+	UtY := new(mat64.Dense)
+	UtY.Mul(U.T(), Y)
+	_, cols := UtY.Dims()
+	for i, e := range singValues {
+		s := 0.0
+		if e > cutoff {
+			s = 1 / e
+		}
+		for j := 0; j < cols; j++ {
+			UtY.Set(i, j, s*UtY.At(i, j))
+		}
+	}
+	Theta := new(mat64.Dense)
+	Theta.Mul(&V, UtY)
+	return Theta, nil
+}
+
+// svdCutoff returns the threshold under which
+// singular values of an (r x c) matrix
+// are considered zero.
+func svdCutoff(singValues []float64, r, c int, rcond float64) float64 {
+	if len(singValues) == 0 {
+		return 0
+	}
+	if rcond <= 0 {
+		n := r
+		if c > n {
+			n = c
+		}
+		rcond = epsilon64 * float64(n)
+	}
+	// Singular values are in descending order.
+	return rcond * math.Abs(singValues[0])
+}
@@ -58,11 +58,22 @@ var pinvCases = []pinvCase{
 	},
 }
 
-func TestPinv(t *testing.T) {
+// identity returns the (n x n) identity matrix.
+func identity(n int) *mat64.Dense {
+	I := mat64.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		I.Set(i, i, 1)
+	}
+	return I
+}
+
+// Solving against the identity
+// gives the pseudo inverse.
+func TestLeastSquares_pinv(t *testing.T) {
 	for _, c := range pinvCases {
 		E := mat64.NewDense(c.c, c.r, c.expected)
 		T := mat64.NewDense(c.r, c.c, c.test)
-		R, err := pinv(T)
+		R, err := leastSquares(T, identity(c.r), 0)
 		if err != nil {
 			t.Error(err)
 		}
@@ -82,11 +93,11 @@ func TestPinv(t *testing.T) {
 // Test that pinv(A) satisfies
 // Moore–Penrose pseudoinverse properties:
 // A x pinv(A) x A = A
-func TestPinv_properties(t *testing.T) {
+func TestLeastSquares_properties(t *testing.T) {
 	for _, c := range pinvCases {
 		A := mat64.NewDense(c.r, c.c, c.test)
 		P := new(mat64.Dense)
-		Ap, err := pinv(A)
+		Ap, err := leastSquares(A, identity(c.r), 0)
 		if err != nil {
 			t.Error(err)
 		}
@@ -98,3 +109,41 @@ func TestPinv_properties(t *testing.T) {
 		}
 	}
 }
+
+func TestLeastSquares_rcond(t *testing.T) {
+	// Nearly singular matrix, second singular value
+	// is ~1e-10 times the first one.
+	A := mat64.NewDense(2, 2, []float64{1, 1, 1, 1 + 1e-10})
+	P, err := leastSquares(A, identity(2), 1e-8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Truncated pseudo inverse is pinv of
+	// the rank 1 matrix of ones divided by 4.
+	E := mat64.NewDense(2, 2, []float64{0.25, 0.25, 0.25, 0.25})
+	if !mat64.EqualApprox(P, E, epsilon) {
+		t.Errorf("expected:\n%v\ngot:\n%v", mat64.Formatted(E), mat64.Formatted(P))
+	}
+}
+
+func TestLeastSquares_collinear(t *testing.T) {
+	// Second and third columns are
+	// the same feature, y = 1 + 2*x.
+	X := mat64.NewDense(4, 3, []float64{
+		1, 1, 1,
+		1, 2, 2 + 1e-12,
+		1, 3, 3,
+		1, 4, 4 - 1e-12,
+	})
+	Y := mat64.NewDense(4, 1, []float64{3, 5, 7, 9})
+	Theta, err := leastSquares(X, Y, 1e-8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Minimum norm solution splits
+	// slope between collinear features.
+	E := mat64.NewDense(3, 1, []float64{1, 1, 1})
+	if !mat64.EqualApprox(Theta, E, epsilon) {
+		t.Errorf("expected:\n%v\ngot:\n%v", mat64.Formatted(E), mat64.Formatted(Theta))
+	}
+}
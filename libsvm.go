@@ -0,0 +1,181 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadLibSVM reads data in the sparse LibSVM/SVMLight format:
+//
+//	label index1:value1 index2:value2 ...
+//
+// where indexes start at 1, and loads it in memory
+// as a SchemaTable with nFeatures numeric columns,
+// zero for features not listed, followed by the label column.
+// If nFeatures <= 0 it is the highest index found.
+//
+// label sets the type of label column,
+// if it is AutoColumn labels are numeric
+// only if all of them are numbers.
+// Comments starting with # are ignored.
+func ReadLibSVM(r io.Reader, nFeatures int, label ColumnType) (SchemaTable, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+	type sample struct {
+		label    string
+		indexes  []int
+		features []float64
+	}
+	var samples []sample
+	maxIndex := 0
+	numericLabels := true
+	s := bufio.NewScanner(dr)
+	n := 0
+	for s.Scan() {
+		n++
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		smp := sample{label: fields[0]}
+		if _, err := strconv.ParseFloat(smp.label, 64); err != nil {
+			numericLabels = false
+		}
+		for _, f := range fields[1:] {
+			sep := strings.IndexByte(f, ':')
+			if sep < 0 {
+				return nil, fmt.Errorf("learn: line %d: malformed feature %q", n, f)
+			}
+			index, err := strconv.Atoi(f[:sep])
+			if err != nil || index < 1 {
+				return nil, fmt.Errorf("learn: line %d: invalid index in %q", n, f)
+			}
+			v, err := strconv.ParseFloat(f[sep+1:], 64)
+			if err != nil {
+				return nil, &ParseError{Row: n, Column: index, Value: f[sep+1:], Type: NumericColumn}
+			}
+			if nFeatures > 0 && index > nFeatures {
+				return nil, fmt.Errorf("learn: line %d: index %d exceeds %d features", n, index, nFeatures)
+			}
+			if index > maxIndex {
+				maxIndex = index
+			}
+			smp.indexes = append(smp.indexes, index)
+			smp.features = append(smp.features, v)
+		}
+		samples = append(samples, smp)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if nFeatures <= 0 {
+		nFeatures = maxIndex
+	}
+	if label == AutoColumn {
+		label = CategoricalColumn
+		if numericLabels {
+			label = NumericColumn
+		}
+	}
+	schema := Schema{
+		Columns: make([]Column, nFeatures+1),
+		Label:   nFeatures,
+	}
+	for j := 0; j < nFeatures; j++ {
+		schema.Columns[j] = Column{Name: "f" + strconv.Itoa(j+1), Type: NumericColumn}
+	}
+	schema.Columns[nFeatures] = Column{Name: "label", Type: label}
+	data := make(MemoryTable, len(samples))
+	for i, smp := range samples {
+		row := make([]interface{}, nFeatures+1)
+		for j := 0; j < nFeatures; j++ {
+			row[j] = 0.0
+		}
+		for k, index := range smp.indexes {
+			row[index-1] = smp.features[k]
+		}
+		if label == NumericColumn {
+			f, err := strconv.ParseFloat(smp.label, 64)
+			if err != nil {
+				return nil, &ParseError{Row: i + 1, Column: 1, Value: smp.label, Type: NumericColumn}
+			}
+			row[nFeatures] = f
+		} else {
+			row[nFeatures] = smp.label
+		}
+		data[i] = row
+	}
+	return &schemaTable{
+		MemoryTable: data,
+		schema:      schema,
+	}, nil
+}
+
+// WriteLibSVM writes data to w in the sparse
+// LibSVM/SVMLight format.
+//
+// Label is taken from the Schema if data is a SchemaTable,
+// otherwise from the last column.
+// Features must be numeric, zero and Missing
+// values are not written.
+func WriteLibSVM(w io.Writer, data Table) error {
+	nRows, nColumns := data.Caps()
+	label := nColumns - 1
+	if st, ok := data.(SchemaTable); ok {
+		label = st.Schema().Label
+	}
+	if label < 0 {
+		return errors.New("learn: no label column to write")
+	}
+	bw := bufio.NewWriter(w)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		if len(row) != nColumns {
+			return rowWidthErr(i, len(row), nColumns)
+		}
+		l, err := formatValue(row[label], "")
+		if err != nil {
+			return err
+		}
+		if l == "" || strings.ContainsAny(l, " \t") {
+			return fmt.Errorf("learn: invalid LibSVM label %q in row %d", l, i)
+		}
+		bw.WriteString(l)
+		index := 0
+		for j, e := range row {
+			if j == label {
+				continue
+			}
+			index++
+			switch v := e.(type) {
+			case float64:
+				if v != 0 {
+					fmt.Fprintf(bw, " %d:%s", index, strconv.FormatFloat(v, 'g', -1, 64))
+				}
+			case Missing:
+			default:
+				return unknownTypeErr(e)
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLibSVM(t *testing.T) {
+	input := "+1 1:0.5 3:2\n# comment\n-1 2:1.5 # trailing\n"
+	data, err := ReadLibSVM(strings.NewReader(input), 0, CategoricalColumn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MemoryTable{
+		{0.5, 0.0, 2.0, "+1"},
+		{0.0, 1.5, 0.0, "-1"},
+	}
+	for i, e := range expected {
+		row, err := data.Row(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(row, e) {
+			t.Fatalf("row %d: expected %v, got %v", i, e, row)
+		}
+	}
+	if s := data.Schema(); s.Label != 3 || s.Columns[3].Type != CategoricalColumn {
+		t.Fatalf("unexpected schema: %v", s)
+	}
+	var buf bytes.Buffer
+	if err := WriteLibSVM(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if want := "+1 1:0.5 3:2\n-1 2:1.5\n"; buf.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+	// Numeric labels are inferred.
+	data, err = ReadLibSVM(strings.NewReader(input), 5, AutoColumn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, _ := data.Row(0)
+	if len(row) != 6 || row[5] != 1.0 {
+		t.Fatalf("unexpected row: %v", row)
+	}
+	for _, ragged := range []MemoryTable{{{1.0, "a"}, {2.0}}, {{1.0, "a"}, {2.0, "b", "c"}}} {
+		if err := WriteLibSVM(&buf, ragged); err == nil {
+			t.Errorf("expected an error writing %v", ragged)
+		}
+	}
+}
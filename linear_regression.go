@@ -6,6 +6,8 @@ package learn
 
 import (
 	"errors"
+	"fmt"
+	"math"
 
 	"github.com/gonum/matrix/mat64"
 )
@@ -20,42 +22,61 @@ type linearRegression struct {
 	theta mat64.Matrix
 }
 
+// predictBlockSize is the number of rows
+// multiplied at once by Predict.
+const predictBlockSize = 1024
+
 // Predict given a Table with samples in its rows:
-// 	x1 x2 ... xn
+//
+//	x1 x2 ... xn
 //	...
-// 	x1 x2 ... xn
+//	x1 x2 ... xn
+//
 // returns a slice of float of estimated y values.
 //
-// Features must be stored as float64 in Table.
+// Features must be stored as float64 in Table
+// and their number must match the one used in training.
+// Rows are multiplied by coefficients in blocks
+// of predictBlockSize rows.
 func (lr *linearRegression) Predict(t Table) ([]float64, error) {
-	// FIXME Table confuses here? use []float64
-	// and return a single float64?
 	m, _ := t.Caps()
-	var y mat64.Dense
+	// +1 for x0
+	o, _ := lr.theta.Dims()
 	ys := make([]float64, m)
-	for i := 0; i < m; i++ {
-		row, err := t.Row(i)
-		if err != nil {
-			return nil, err
+	block := make([]float64, predictBlockSize*o)
+	for start := 0; start < m; start += predictBlockSize {
+		end := start + predictBlockSize
+		if end > m {
+			end = m
 		}
-		// +1 for x0
-		n := len(row) + 1
-		a := make([]float64, n)
-		// for x0
-		a[0] = 1
-		for i, e := range row {
-			f, ok := e.(float64)
-			if !ok {
-				return nil, unknownTypeErr(f)
+		b := end - start
+		for i := 0; i < b; i++ {
+			row, err := t.Row(start + i)
+			if err != nil {
+				return nil, err
+			}
+			if len(row) != o-1 {
+				return nil, fmt.Errorf("learn: row %d has %d features, model expects %d", start+i, len(row), o-1)
+			}
+			a := block[i*o : (i+1)*o]
+			// for x0
+			a[0] = 1
+			for j, e := range row {
+				if IsMissing(e) {
+					return nil, missingValueErr(start+i, j)
+				}
+				f, ok := e.(float64)
+				if !ok {
+					return nil, unknownTypeErr(e)
+				}
+				a[j+1] = f
 			}
-			a[i+1] = f
 		}
-		X := mat64.NewDense(1, n, a)
-		// y is a (1 x 1) matrix
-		y.Mul(X, lr.theta)
-		//fT := mat64.Formatted(X)
-		//trace.Printf("X:\n%v\n", fT)
-		ys[i] = y.At(0, 0)
+		X := mat64.NewDense(b, o, block[:b*o])
+		// Y shares storage with ys
+		// so that no copy is needed.
+		Y := mat64.NewDense(b, 1, ys[start:end])
+		Y.Mul(X, lr.theta)
 	}
 	return ys, nil
 }
@@ -67,11 +88,40 @@ func (lr *linearRegression) Predict(t Table) ([]float64, error) {
 // Last element in the row MUST be
 // the observed value of dependent variable y.
 //
-// Current implementation uses normal equation,
+// Current implementation solves least squares
+// with SVD of the design matrix,
 // data normalization is not necessary.
 //
 // Table will be loaded in memory.
 func NewLinearRegression(Data Table) (Regression, error) {
+	return NewLinearRegressionTol(Data, 0)
+}
+
+// NewLinearRegressionTol is like NewLinearRegression
+// but singular values of the design matrix smaller than
+// rcond times the largest one are considered zero.
+// This avoids huge coefficients
+// for nearly collinear features.
+// If rcond <= 0 a default tolerance
+// that accounts for rounding errors is used.
+func NewLinearRegressionTol(Data Table, rcond float64) (Regression, error) {
+	X, Y, err := designMatrix(Data)
+	if err != nil {
+		return nil, err
+	}
+	Theta, err := leastSquares(X, Y, rcond)
+	if err != nil {
+		return nil, err
+	}
+	return &linearRegression{
+		theta: Theta,
+	}, nil
+}
+
+// designMatrix builds design matrix X
+// and vector of observed values Y from
+// Data's rows.
+func designMatrix(Data Table) (X, Y *mat64.Dense, err error) {
 	// m: number of samples
 	// n: number of features
 	// X is a representation of design matrix,
@@ -83,14 +133,13 @@ func NewLinearRegression(Data Table) (Regression, error) {
 	// The 'ones column' is added.
 	// Y is the vector of observed values
 	// of dependent variable.
-	var X, Y *mat64.Dense
 	m, _ := Data.Caps()
 	if m <= 0 {
-		return nil, ErrNoData
+		return nil, nil, ErrNoData
 	}
 	s1, err := Data.Row(0)
 	if err != nil {
-		return nil, ErrNoData
+		return nil, nil, ErrNoData
 	}
 	// o = n+1 because table stores
 	// values of y in the last column.
@@ -104,16 +153,19 @@ func NewLinearRegression(Data Table) (Regression, error) {
 		row := make([]float64, o)
 		r, err := Data.Row(i)
 		if err != nil {
-			return nil, ErrNoData
+			return nil, nil, ErrNoData
 		}
 		// x0
 		row[0] = 1
 		for j, e := range r {
+			if IsMissing(e) {
+				return nil, nil, missingValueErr(i, j)
+			}
 			if f, ok := e.(float64); !ok {
-				return nil, unknownTypeErr(e)
+				return nil, nil, unknownTypeErr(e)
 			} else {
 				if j >= o {
-					return nil, errors.New("index out of range")
+					return nil, nil, errors.New("index out of range")
 				} else if j == o-1 {
 					// y element
 					yRows[i] = f
@@ -125,19 +177,96 @@ func NewLinearRegression(Data Table) (Regression, error) {
 		X.SetRow(i, row)
 	}
 	Y = mat64.NewDense(m, 1, yRows)
-	// theta = pinv(X'*X)*X'*y
-	P := new(mat64.Dense)
-	Theta := new(mat64.Dense)
-	// (n x n)
-	P.Mul(X.T(), X)
-	Pi, err := pinv(P)
+	return X, Y, nil
+}
+
+// NewWeightedLinearRegression returns Regression type
+// for linear regression fitted with weighted least squares,
+// where the i-th sample contributes to the loss proportionally
+// to weights[i].
+//
+// Data has the same layout required by NewLinearRegression.
+// If weights is nil, sample weights are taken from the
+// field before y, so rows must be stored as:
+//
+//	x1 x2 ... xn w y
+//
+// and the weight column is not used as a feature.
+// Tables passed to Predict must store x1 ... xn only.
+func NewWeightedLinearRegression(Data Table, weights []float64) (Regression, error) {
+	X, Y, weights, err := weightedDesignMatrix(Data, weights)
+	if err != nil {
+		return nil, err
+	}
+	Theta, err := weightedLeastSquares(X, Y, weights, 0)
 	if err != nil {
 		return nil, err
 	}
-	Theta.Product(Pi, X.T(), Y)
-	//fT := mat64.Formatted(Theta)
-	//trace.Printf("Theta:\n%v\n", fT)
 	return &linearRegression{
 		theta: Theta,
 	}, nil
 }
+
+// weightedDesignMatrix is like designMatrix
+// but also returns checked sample weights,
+// extracting them from Data if weights is nil.
+func weightedDesignMatrix(Data Table, weights []float64) (X, Y *mat64.Dense, w []float64, err error) {
+	X, Y, err = designMatrix(Data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m, n := X.Dims()
+	if weights == nil {
+		// Weight column is the last
+		// in design matrix.
+		if n < 2 {
+			return nil, nil, nil, errors.New("learn: no column for sample weights")
+		}
+		weights = mat64.Col(nil, n-1, X)
+		X = mat64.DenseCopyOf(X.View(0, 0, m, n-1))
+	}
+	if err := checkWeights(weights, m); err != nil {
+		return nil, nil, nil, err
+	}
+	return X, Y, weights, nil
+}
+
+// checkWeights verifies that there are
+// m finite non negative sample weights,
+// not all of them zero.
+func checkWeights(weights []float64, m int) error {
+	if len(weights) != m {
+		return fmt.Errorf("learn: %d samples but %d weights", m, len(weights))
+	}
+	var total float64
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("learn: invalid weight %v for sample %d", w, i)
+		}
+		total += w
+	}
+	if total == 0 {
+		return errors.New("learn: all weights are zero")
+	}
+	return nil
+}
+
+// weightedLeastSquares returns theta that minimizes
+// sum of squared residuals weighted by weights.
+// Rows of X and Y are scaled by square root
+// of weights and solved with leastSquares.
+func weightedLeastSquares(X, Y *mat64.Dense, weights []float64, rcond float64) (*mat64.Dense, error) {
+	_, n := X.Dims()
+	WX := new(mat64.Dense)
+	WX.Clone(X)
+	WY := new(mat64.Dense)
+	WY.Clone(Y)
+	for i, w := range weights {
+		sw := math.Sqrt(w)
+		for j := 0; j < n; j++ {
+			WX.Set(i, j, sw*X.At(i, j))
+		}
+		WY.Set(i, 0, sw*Y.At(i, 0))
+	}
+	return leastSquares(WX, WY, rcond)
+}
@@ -18,6 +18,16 @@ func loadDataset(t *testing.T) Table {
 	return trainData
 }
 
+// cloneTable returns a copy of data,
+// so tests can modify shared fixtures.
+func cloneTable(data MemoryTable) MemoryTable {
+	out := make(MemoryTable, len(data))
+	for i, row := range data {
+		out[i] = copyRow(row)
+	}
+	return out
+}
+
 const floatTolerance = 1e-7
 
 func floatsAreEqual(a, b float64) bool {
@@ -44,8 +54,101 @@ func TestLinearRegression_Predict(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	wanted := 293081.464335
+	// Exact least squares solution.
+	wanted := 293081.4643348961
 	if !floatsAreEqual(y[0], wanted) {
 		t.Fatalf("wrong prediction, want: %f got: %f\n", y[0], wanted)
 	}
 }
+
+func TestWeightedLinearRegression(t *testing.T) {
+	data := outliers
+	weights := make([]float64, len(data))
+	// Weighted data, stores weight
+	// before y.
+	var wData MemoryTable
+	for i, row := range data {
+		weights[i] = 1
+		if i == 3 || i == 17 {
+			// Ignore outliers.
+			weights[i] = 0
+		}
+		wData = append(wData, []interface{}{row[0], weights[i], row[1]})
+	}
+	fromSlice, err := NewWeightedLinearRegression(data, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromColumn, err := NewWeightedLinearRegression(wData, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test MemoryTable = [][]interface{}{{10.0}, {30.0}}
+	for _, lr := range []Regression{fromSlice, fromColumn} {
+		y, err := lr.Predict(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !floatsAreEqual(y[0], 21) || !floatsAreEqual(y[1], 61) {
+			t.Errorf("expected [21 61], got %v", y)
+		}
+	}
+	_, err = NewWeightedLinearRegression(data, weights[1:])
+	if err == nil {
+		t.Fatal("expected an error for wrong number of weights")
+	}
+}
+
+func TestLinearRegression_PredictBlocks(t *testing.T) {
+	// y = 1 + 2*x1 - x2
+	var train MemoryTable
+	for i := 0; i < 10; i++ {
+		x1, x2 := float64(i), float64(i*i)
+		train = append(train, []interface{}{x1, x2, 1 + 2*x1 - x2})
+	}
+	lr, err := NewLinearRegression(train)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// More rows than a single block.
+	m := predictBlockSize*2 + 7
+	var test MemoryTable = make([][]interface{}, m)
+	for i := range test {
+		test[i] = []interface{}{float64(i), 0.5}
+	}
+	y, err := lr.Predict(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range y {
+		if want := 0.5 + 2*float64(i); math.Abs(e-want) > 1e-6 {
+			t.Fatalf("row %d: expected %f, got %f", i, want, e)
+		}
+	}
+	test[m-1] = []interface{}{1.0}
+	if _, err := lr.Predict(test); err == nil {
+		t.Fatal("expected an error for wrong number of features")
+	}
+}
+
+func BenchmarkLinearRegression_Predict(b *testing.B) {
+	var train MemoryTable
+	for i := 0; i < 10; i++ {
+		x1, x2 := float64(i), float64(i*i)
+		train = append(train, []interface{}{x1, x2, 1 + 2*x1 - x2})
+	}
+	lr, err := NewLinearRegression(train)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var test MemoryTable = make([][]interface{}, 100000)
+	for i := range test {
+		test[i] = []interface{}{float64(i), 0.5}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := lr.Predict(test); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gonum/matrix"
+	"github.com/gonum/matrix/mat64"
+)
+
+// PCA is a Step that projects numeric columns of a Table
+// on their principal components, computed with the SVD
+// of centered training data. Projected columns are
+// named pc1...pck and come first, followed by
+// the columns that are not projected, like the label.
+//
+// If Whiten is true components are scaled to unit variance,
+// useful before kNN or Kmc that weight features equally.
+// Transform returns a new Table in memory.
+type PCA struct {
+	// Components is the number of components
+	// to keep, all of them if <= 0.
+	Components int
+	Whiten     bool
+	// Columns to project, if nil all numeric
+	// columns but the label are projected.
+	Columns  []int
+	columns  []int // Projected columns, set by Fit.
+	mean     []float64
+	vectors  *mat64.Dense // Principal axes as columns.
+	variance []float64
+	ratio    []float64
+}
+
+// numericMatrix returns values of columns of data
+// in a matrix with one row per Table's row.
+func numericMatrix(data Table, columns []int) (*mat64.Dense, error) {
+	nRows, _ := data.Caps()
+	if nRows == 0 {
+		return nil, ErrNoData
+	}
+	X := mat64.NewDense(nRows, len(columns), nil)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		for k, j := range columns {
+			if j >= len(row) {
+				return nil, shortRowErr(i, j)
+			}
+			switch v := row[j].(type) {
+			case float64:
+				X.Set(i, k, v)
+			case Missing:
+				return nil, missingValueErr(i, j)
+			default:
+				return nil, unknownTypeErr(v)
+			}
+		}
+	}
+	return X, nil
+}
+
+// numericCandidates returns data's columns, label
+// excluded, whose first non missing value is a number.
+func numericCandidates(data Table) ([]int, error) {
+	nRows, nColumns := data.Caps()
+	label := NewFrame(data).Schema().Label
+	numeric := make([]bool, nColumns)
+	seen := make([]bool, nColumns)
+	left := nColumns
+	for i := 0; i < nRows && left > 0; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		for j, e := range row {
+			if j >= nColumns || seen[j] || IsMissing(e) {
+				continue
+			}
+			_, numeric[j] = e.(float64)
+			seen[j] = true
+			left--
+		}
+	}
+	var columns []int
+	for j, ok := range numeric {
+		if ok && j != label {
+			columns = append(columns, j)
+		}
+	}
+	return columns, nil
+}
+
+// Fit computes principal components
+// of data's numeric columns.
+func (p *PCA) Fit(data Table) error {
+	columns := p.Columns
+	if columns == nil {
+		var err error
+		if columns, err = numericCandidates(data); err != nil {
+			return err
+		}
+	}
+	if len(columns) == 0 {
+		return errors.New("learn: no numeric columns to project")
+	}
+	X, err := numericMatrix(data, columns)
+	if err != nil {
+		return err
+	}
+	n, c := X.Dims()
+	mean := make([]float64, c)
+	for j := range mean {
+		mean[j] = mat64.Sum(X.ColView(j)) / float64(n)
+		for i := 0; i < n; i++ {
+			X.Set(i, j, X.At(i, j)-mean[j])
+		}
+	}
+	svd := new(mat64.SVD)
+	if ok := svd.Factorize(X, matrix.SVDThin); !ok {
+		return errors.New("learn: not factorizable")
+	}
+	values := svd.Values(nil)
+	var V mat64.Dense
+	V.VFromSVD(svd)
+	k := p.Components
+	if k <= 0 || k > len(values) {
+		k = len(values)
+	}
+	var total float64
+	variance := make([]float64, len(values))
+	for i, s := range values {
+		variance[i] = s * s / float64(n-1)
+		if n == 1 {
+			variance[i] = 0
+		}
+		total += variance[i]
+	}
+	p.ratio = make([]float64, k)
+	for i := range p.ratio {
+		if total > 0 {
+			p.ratio[i] = variance[i] / total
+		}
+	}
+	p.columns = columns
+	p.mean = mean
+	p.variance = variance[:k]
+	p.vectors = mat64.DenseCopyOf(V.View(0, 0, c, k))
+	return nil
+}
+
+// ExplainedVariance returns the variance
+// of training data along each kept component.
+func (p *PCA) ExplainedVariance() []float64 {
+	return p.variance
+}
+
+// ExplainedVarianceRatio returns the fraction
+// of total variance of training data
+// explained by each kept component.
+func (p *PCA) ExplainedVarianceRatio() []float64 {
+	return p.ratio
+}
+
+// Transform returns data with numeric
+// columns projected on principal components.
+func (p *PCA) Transform(data Table) (Table, error) {
+	if p.vectors == nil {
+		return nil, errors.New("learn: PCA is not fitted")
+	}
+	X, err := numericMatrix(data, p.columns)
+	if err != nil {
+		return nil, err
+	}
+	n, c := X.Dims()
+	for j := 0; j < c; j++ {
+		for i := 0; i < n; i++ {
+			X.Set(i, j, X.At(i, j)-p.mean[j])
+		}
+	}
+	var Z mat64.Dense
+	Z.Mul(X, p.vectors)
+	_, k := Z.Dims()
+	projected := make(map[int]bool, len(p.columns))
+	for _, j := range p.columns {
+		projected[j] = true
+	}
+	s := NewFrame(data).Schema()
+	schema := Schema{Label: -1}
+	for i := 0; i < k; i++ {
+		schema.Columns = append(schema.Columns, Column{Name: "pc" + strconv.Itoa(i+1), Type: NumericColumn})
+	}
+	for j, col := range s.Columns {
+		if projected[j] {
+			continue
+		}
+		if j == s.Label {
+			schema.Label = len(schema.Columns)
+		}
+		schema.Columns = append(schema.Columns, col)
+	}
+	out := make(MemoryTable, n)
+	for i := range out {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		r := make([]interface{}, 0, len(schema.Columns))
+		for l := 0; l < k; l++ {
+			z := Z.At(i, l)
+			if p.Whiten && p.variance[l] > 0 {
+				z /= math.Sqrt(p.variance[l])
+			}
+			r = append(r, z)
+		}
+		for j, e := range row {
+			if !projected[j] {
+				r = append(r, e)
+			}
+		}
+		out[i] = r
+	}
+	return &schemaTable{
+		MemoryTable: out,
+		schema:      schema,
+	}, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPCA(t *testing.T) {
+	// Points on the line y = 2x with small noise.
+	data := MemoryTable{
+		{1.0, 2.1, "a"},
+		{2.0, 3.9, "a"},
+		{3.0, 6.0, "b"},
+		{4.0, 8.1, "b"},
+		{5.0, 9.9, "b"},
+	}
+	p := &PCA{Components: 1}
+	if err := p.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	ratio := p.ExplainedVarianceRatio()
+	if len(ratio) != 1 || ratio[0] < 0.99 {
+		t.Errorf("first component must explain most variance, got %v", ratio)
+	}
+	reduced, err := p.Transform(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, c := reduced.Caps(); c != 2 {
+		t.Fatalf("expected 2 columns, got %d", c)
+	}
+	st := reduced.(SchemaTable)
+	if names := st.Schema().Names(); names[0] != "pc1" || st.Schema().Label != 1 {
+		t.Errorf("wrong schema: %v", st.Schema())
+	}
+	// Projections are centered and have
+	// the explained variance.
+	var s ColumnStats
+	for i := 0; i < 5; i++ {
+		row, _ := reduced.Row(i)
+		s.Add(row[0])
+		if row[1] != data[i][2] {
+			t.Errorf("label not preserved in row %d: %v", i, row)
+		}
+	}
+	if math.Abs(s.Mean()) > 1e-9 || !floatsAreEqual(s.Variance(), p.ExplainedVariance()[0]) {
+		t.Errorf("wrong projections: mean %v, variance %v", s.Mean(), s.Variance())
+	}
+}
+
+func TestPCA_whiten(t *testing.T) {
+	data := MemoryTable{
+		{1.0, 0.0, 3.0},
+		{-2.0, 1.0, 1.0},
+		{0.5, -3.0, 2.0},
+		{4.0, 2.0, -1.0},
+	}
+	p := &PCA{Whiten: true, Columns: []int{0, 1, 2}}
+	reduced, err := NewPipeline(p).FitTransform(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, k := reduced.Caps()
+	for l := 0; l < k; l++ {
+		var s ColumnStats
+		for i := 0; i < 4; i++ {
+			row, _ := reduced.Row(i)
+			s.Add(row[l])
+		}
+		if !floatsAreEqual(s.Variance(), 1) {
+			t.Errorf("component %d has variance %v", l, s.Variance())
+		}
+	}
+	if _, err := p.Transform(MemoryTable{{1.0, Missing{}, 2.0}}); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestPCA_refit(t *testing.T) {
+	p := &PCA{}
+	if err := p.Fit(MemoryTable{{1.0, 2.0, 3.0, "a"}, {2.0, 1.0, 5.0, "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Columns chosen by Fit must not
+	// be reused fitting other data.
+	data := MemoryTable{{1.0, "a"}, {2.0, "b"}, {4.0, "b"}}
+	if err := p.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if p.Columns != nil {
+		t.Errorf("Columns set by Fit: %v", p.Columns)
+	}
+	if _, err := p.Transform(data); err != nil {
+		t.Fatal(err)
+	}
+	_, err := p.Transform(MemoryTable{{}})
+	if err == nil || err.Error() != "learn: row 0 has no column 0" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNumericCandidates(t *testing.T) {
+	data := MemoryTable{
+		{Missing{}, 1.0, Missing{}, "a"},
+		{2.0, 3.0, "x", "b"},
+	}
+	columns, err := numericCandidates(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{0, 1}; !reflect.DeepEqual(columns, expected) {
+		t.Errorf("expected %v, got %v", expected, columns)
+	}
+	// Missing values are not dropped
+	// silently but must be imputed.
+	if err := (&PCA{}).Fit(data); err == nil || !strings.Contains(err.Error(), "missing value in row 0, column 0") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import "errors"
+
+// Step is a preprocessing step that learns
+// its parameters from training data and
+// then applies them to any Table.
+//
+// Transform consumes the passed Table: Steps that keep
+// its columns, like Normalizer, Scaler, Imputer and Binner,
+// modify it in place using Update(), as Normalize does,
+// so that Tables larger than memory can be transformed.
+// Steps that change columns, like encoders, FeatureSelector
+// and PCA, return a new Table or a view of the passed one.
+// In both cases only the returned Table must be used
+// afterwards, a copy must be passed to keep the original.
+type Step interface {
+	Fit(Table) error
+	Transform(Table) (Table, error)
+}
+
+// FitTransformer is implemented by Steps
+// that transform training data differently
+// from data seen after Fit, Pipeline uses it
+// in place of Fit followed by Transform.
+type FitTransformer interface {
+	FitTransform(Table) (Table, error)
+}
+
+// Normalizer is a Step that normalizes
+// Tables as Normalize does.
+// Its fields are set by Fit.
+type Normalizer struct {
+	Mu           []float64
+	Sigma        []float64
+	Vocabularies []Vocabulary
+}
+
+// Fit computes means, standard deviations
+// and vocabularies of data's columns.
+func (n *Normalizer) Fit(data Table) error {
+	mu, sigma, vocabularies, err := normalizeStats(data)
+	if err != nil {
+		return err
+	}
+	n.Mu, n.Sigma, n.Vocabularies = mu, sigma, vocabularies
+	return nil
+}
+
+// Transform normalizes data with
+// values computed by Fit and returns it.
+func (n *Normalizer) Transform(data Table) (Table, error) {
+	if n.Mu == nil || n.Sigma == nil {
+		return nil, errors.New("learn: Normalizer is not fitted")
+	}
+	if err := applyNormalize(data, n.Mu, n.Sigma, n.Vocabularies); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Pipeline chains Steps, each one fitted on
+// the output of the previous ones.
+// It is a Step itself.
+type Pipeline struct {
+	Steps []Step
+}
+
+// NewPipeline returns a Pipeline of steps.
+func NewPipeline(steps ...Step) *Pipeline {
+	return &Pipeline{Steps: steps}
+}
+
+// Fit fits Pipeline's steps on data.
+// Steps after the first are fitted on data
+// transformed by previous ones, so data
+// is modified as by FitTransform.
+func (p *Pipeline) Fit(data Table) error {
+	_, err := p.FitTransform(data)
+	return err
+}
+
+// FitTransform fits Pipeline's steps on data
+// and returns it transformed, ready to be passed
+// to NewkNN, NewLinearRegression or Kmc.
+// Steps that are FitTransformers are fitted
+// with their FitTransform.
+func (p *Pipeline) FitTransform(data Table) (Table, error) {
+	for _, s := range p.Steps {
+		var err error
+		if ft, ok := s.(FitTransformer); ok {
+			data, err = ft.FitTransform(data)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := s.Fit(data); err != nil {
+			return nil, err
+		}
+		data, err = s.Transform(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Transform applies fitted steps
+// to data in order.
+func (p *Pipeline) Transform(data Table) (Table, error) {
+	for _, s := range p.Steps {
+		var err error
+		data, err = s.Transform(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Classifier returns a Classifier that transforms
+// copies of Tables with p before passing them to c.
+func (p *Pipeline) Classifier(c Classifier) Classifier {
+	return &pipelineClassifier{p, c}
+}
+
+// Regression returns a Regression that transforms
+// copies of Tables with p before passing them to r.
+func (p *Pipeline) Regression(r Regression) Regression {
+	return &pipelineRegression{p, r}
+}
+
+type pipelineClassifier struct {
+	p *Pipeline
+	c Classifier
+}
+
+// Predict transforms a copy of t,
+// so that t is left untouched.
+func (pc *pipelineClassifier) Predict(t Table) (Table, error) {
+	t, err := copyTable(t)
+	if err != nil {
+		return nil, err
+	}
+	t, err = pc.p.Transform(t)
+	if err != nil {
+		return nil, err
+	}
+	return pc.c.Predict(t)
+}
+
+type pipelineRegression struct {
+	p *Pipeline
+	r Regression
+}
+
+// Predict transforms a copy of t,
+// so that t is left untouched.
+func (pr *pipelineRegression) Predict(t Table) ([]float64, error) {
+	t, err := copyTable(t)
+	if err != nil {
+		return nil, err
+	}
+	t, err = pr.p.Transform(t)
+	if err != nil {
+		return nil, err
+	}
+	return pr.r.Predict(t)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	train := cloneTable(missingData)
+	p := NewPipeline(&Imputer{Strategy: ImputeMean}, &Normalizer{})
+	if _, err := p.FitTransform(train); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range train {
+		for _, e := range row {
+			if IsMissing(e) {
+				t.Fatalf("missing value left in %v", row)
+			}
+		}
+	}
+	test := MemoryTable{{Missing{}, "b", 80.0 / 3}}
+	if _, err := p.Transform(test); err != nil {
+		t.Fatal(err)
+	}
+	// Imputed mean is normalized to 0.
+	if !floatsAreEqual(test[0][0].(float64), 0) || !floatsAreEqual(test[0][2].(float64), 0) {
+		t.Errorf("expected zeroes, got %v", test[0])
+	}
+	if c := test[0][1].(*Category); c.Index() != 1 {
+		t.Errorf("expected index 1, got %d", c.Index())
+	}
+}
+
+func TestPipeline_Classifier(t *testing.T) {
+	trainSet, err := ReadAllCSV("datasets/iris.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPipeline(&Normalizer{})
+	trainSet, err = p.FitTransform(trainSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	knn, err := NewkNN(trainSet, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clf := p.Classifier(knn)
+	test := MemoryTable{{5.2, 3.4, 1.3, 0.1}}
+	// Predicting twice must not
+	// normalize test data twice.
+	for i := 0; i < 2; i++ {
+		prediction, err := clf.Predict(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := prediction.Row(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r[0] != "setosa" {
+			t.Errorf("want: setosa, got: %s", r[0])
+		}
+	}
+	if !reflect.DeepEqual(test, MemoryTable{{5.2, 3.4, 1.3, 0.1}}) {
+		t.Errorf("test data modified: %v", test)
+	}
+}
+
+func TestPipeline_notFitted(t *testing.T) {
+	p := NewPipeline(&Normalizer{})
+	if _, err := p.Transform(missingData); err == nil {
+		t.Error("transforming with a not fitted pipeline must fail")
+	}
+	n := &Normalizer{}
+	if err := n.Fit(MemoryTable{{1.0, 2.0}, {3.0, 5.0}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Transform(MemoryTable{{1.0, 2.0, 3.0}}); err == nil {
+		t.Error("transforming a wider Table must fail")
+	}
+}
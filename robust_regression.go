@@ -0,0 +1,164 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+const (
+	// huberDelta is the default threshold, in units
+	// of residuals' scale, used by Huber loss.
+	// It gives 95% efficiency on normal data.
+	huberDelta = 1.345
+	// irlsMaxIter caps iterations of
+	// iteratively reweighted least squares.
+	irlsMaxIter = 100
+	// irlsTolerance is the maximum change
+	// of coefficients to consider IRLS converged.
+	irlsTolerance = 1e-8
+	// irlsMinResidual is used in place of smaller
+	// absolute residuals to avoid infinite weights.
+	irlsMinResidual = 1e-6
+)
+
+// NewHuberRegression returns a linear Regression
+// fitted minimizing Huber loss, which is quadratic for
+// small residuals and linear for large ones, making
+// the fit robust to outliers.
+//
+// Data has the same layout required by NewLinearRegression.
+// delta is the threshold between quadratic and linear
+// loss in units of residuals' scale, estimated at
+// each iteration with median absolute deviation.
+// If delta <= 0, 1.345 is used.
+//
+// Coefficients are computed with iteratively
+// reweighted least squares.
+func NewHuberRegression(Data Table, delta float64) (Regression, error) {
+	if delta <= 0 {
+		delta = huberDelta
+	}
+	X, Y, err := designMatrix(Data)
+	if err != nil {
+		return nil, err
+	}
+	Theta, err := irls(X, Y, func(residuals, weights []float64) {
+		s := madScale(residuals)
+		if s == 0 {
+			// Perfect fit for most of samples,
+			// keep least squares weights.
+			s = irlsMinResidual
+		}
+		c := delta * s
+		for i, r := range residuals {
+			a := math.Abs(r)
+			if a <= c {
+				weights[i] = 1
+			} else {
+				weights[i] = c / a
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &linearRegression{
+		theta: Theta,
+	}, nil
+}
+
+// NewQuantileRegression returns a linear Regression
+// that estimates q-th conditional quantile
+// of dependent variable, q ∈ (0,1).
+// With q = 0.5 median regression (least absolute deviations)
+// is obtained.
+//
+// Data has the same layout required by NewLinearRegression.
+//
+// Coefficients are computed with iteratively
+// reweighted least squares.
+func NewQuantileRegression(Data Table, q float64) (Regression, error) {
+	if q <= 0 || q >= 1 {
+		return nil, errors.New("learn: quantile must be in (0,1)")
+	}
+	X, Y, err := designMatrix(Data)
+	if err != nil {
+		return nil, err
+	}
+	Theta, err := irls(X, Y, func(residuals, weights []float64) {
+		// Check loss ρ(r) = r(q - I(r<0))
+		// is rewritten as w*r^2.
+		for i, r := range residuals {
+			a := math.Max(math.Abs(r), irlsMinResidual)
+			if r >= 0 {
+				weights[i] = q / a
+			} else {
+				weights[i] = (1 - q) / a
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &linearRegression{
+		theta: Theta,
+	}, nil
+}
+
+// irls solves iteratively reweighted least squares
+// starting from the ordinary least squares solution.
+// reweight must update weights from current residuals.
+func irls(X, Y *mat64.Dense, reweight func(residuals, weights []float64)) (*mat64.Dense, error) {
+	m, _ := X.Dims()
+	weights := make([]float64, m)
+	for i := range weights {
+		weights[i] = 1
+	}
+	residuals := make([]float64, m)
+	Theta, err := weightedLeastSquares(X, Y, weights, 0)
+	if err != nil {
+		return nil, err
+	}
+	var Yp mat64.Dense
+	for iter := 0; iter < irlsMaxIter; iter++ {
+		Yp.Mul(X, Theta)
+		for i := range residuals {
+			residuals[i] = Y.At(i, 0) - Yp.At(i, 0)
+		}
+		reweight(residuals, weights)
+		next, err := weightedLeastSquares(X, Y, weights, 0)
+		if err != nil {
+			return nil, err
+		}
+		converged := mat64.EqualApprox(Theta, next, irlsTolerance)
+		Theta = next
+		if converged {
+			break
+		}
+	}
+	return Theta, nil
+}
+
+// madScale estimates standard deviation
+// of residuals with normalized
+// median absolute deviation.
+func madScale(residuals []float64) float64 {
+	s := make([]float64, len(residuals))
+	copy(s, residuals)
+	sort.Float64s(s)
+	med := quantile(s, 0.5)
+	for i, r := range residuals {
+		s[i] = math.Abs(r - med)
+	}
+	sort.Float64s(s)
+	// 0.6745 is the 0.75 quantile
+	// of standard normal distribution.
+	return quantile(s, 0.5) / 0.6745
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"testing"
+)
+
+// outliers are samples of y = 2x + 1
+// with a couple of gross outliers.
+var outliers = MemoryTable{
+	{0.0, 1.0}, {1.0, 3.0}, {2.0, 5.0}, {3.0, 500.0}, {4.0, 9.0},
+	{5.0, 11.0}, {6.0, 13.0}, {7.0, 15.0}, {8.0, 17.0}, {9.0, 19.0},
+	{10.0, 21.0}, {11.0, 23.0}, {12.0, 25.0}, {13.0, 27.0}, {14.0, 29.0},
+	{15.0, 31.0}, {16.0, 33.0}, {17.0, -300.0}, {18.0, 37.0}, {19.0, 39.0},
+}
+
+func TestRobustRegression(t *testing.T) {
+	data := outliers
+	huber, err := NewHuberRegression(data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	median, err := NewQuantileRegression(data, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test MemoryTable = [][]interface{}{{10.0}, {30.0}}
+	for name, r := range map[string]Regression{"huber": huber, "median": median} {
+		y, err := r.Predict(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(y[0]-21) > 1e-2 || math.Abs(y[1]-61) > 1e-2 {
+			t.Errorf("%s: expected [21 61], got %v", name, y)
+		}
+	}
+}
+
+func TestNewQuantileRegression_badQuantile(t *testing.T) {
+	if _, err := NewQuantileRegression(outliers, 1); err == nil {
+		t.Fatal("expected an error for quantile out of (0,1)")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ScaleMethod selects how a Scaler
+// transforms a numerical column.
+type ScaleMethod uint8
+
+const (
+	ScaleStandard ScaleMethod = iota // (x - mean) / standard deviation, as Normalize.
+	ScaleMinMax                      // (x - min) / (max - min), in [0,1].
+	ScaleRobust                      // (x - median) / interquartile range.
+	ScaleMaxAbs                      // x / max(|x|), in [-1,1].
+	ScaleNone                        // Column is left untouched.
+)
+
+func (m ScaleMethod) String() string {
+	switch m {
+	case ScaleStandard:
+		return "standard"
+	case ScaleMinMax:
+		return "min-max"
+	case ScaleRobust:
+		return "robust"
+	case ScaleMaxAbs:
+		return "max-abs"
+	default:
+		return "none"
+	}
+}
+
+// Scaler is a Step that scales numerical columns
+// as (x - center) / scale, where center and scale
+// depend on the ScaleMethod of the column.
+//
+// Columns with zero scale, e.g. constant ones,
+// are only centered so they do not become NaN.
+// Categorical columns and Missing values are left untouched.
+type Scaler struct {
+	Method  ScaleMethod         // Method used for all columns.
+	Columns map[int]ScaleMethod // Methods for specific columns, overriding Method.
+	center  []float64
+	scale   []float64
+}
+
+// method returns the ScaleMethod of j-th column.
+func (s *Scaler) method(j int) ScaleMethod {
+	if m, ok := s.Columns[j]; ok {
+		return m
+	}
+	return s.Method
+}
+
+// Fit computes centers and scales
+// of data's numerical columns.
+// Only ScaleRobust keeps column's values
+// in memory to compute quantiles.
+func (s *Scaler) Fit(data Table) error {
+	nRows, nColumns := data.Caps()
+	stats := make([]ColumnStats, nColumns)
+	values := make([][]float64, nColumns)
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return err
+		}
+		for j, e := range row {
+			if j >= nColumns {
+				break
+			}
+			v, ok := e.(float64)
+			if !ok {
+				continue
+			}
+			stats[j].Add(v)
+			if s.method(j) == ScaleRobust {
+				values[j] = append(values[j], v)
+			}
+		}
+	}
+	s.center = make([]float64, nColumns)
+	s.scale = make([]float64, nColumns)
+	for j := range stats {
+		s.center[j], s.scale[j] = scaleParams(s.method(j), &stats[j], values[j])
+		if s.scale[j] == 0 || math.IsNaN(s.scale[j]) {
+			// Zero variance.
+			s.scale[j] = 1
+		}
+	}
+	return nil
+}
+
+// scaleParams returns center and scale for method m
+// of a column with stats, values are needed by ScaleRobust.
+func scaleParams(m ScaleMethod, stats *ColumnStats, values []float64) (center, scale float64) {
+	if stats.Count == 0 {
+		return 0, 1
+	}
+	switch m {
+	case ScaleStandard:
+		return stats.Mean(), stats.StdDev()
+	case ScaleMinMax:
+		return stats.Min, stats.Max - stats.Min
+	case ScaleRobust:
+		sorted := make([]float64, len(values))
+		copy(sorted, values)
+		sort.Float64s(sorted)
+		return quantile(sorted, 0.5), quantile(sorted, 0.75) - quantile(sorted, 0.25)
+	case ScaleMaxAbs:
+		return 0, math.Max(math.Abs(stats.Min), math.Abs(stats.Max))
+	default:
+		return 0, 1
+	}
+}
+
+// Transform uses Table's Update() to scale
+// data's numerical columns and returns it.
+// Fit must be called before.
+func (s *Scaler) Transform(data Table) (Table, error) {
+	if s.center == nil {
+		return nil, errors.New("learn: Scaler is not fitted")
+	}
+	nRows, _ := data.Caps()
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		for j, e := range row {
+			v, ok := e.(float64)
+			if !ok || j >= len(s.center) {
+				continue
+			}
+			row[j] = (v - s.center[j]) / s.scale[j]
+		}
+		if err := data.Update(i, row); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import "testing"
+
+var scalerData = MemoryTable{
+	{1.0, -4.0, 5.0, "a"},
+	{2.0, 2.0, 5.0, "b"},
+	{3.0, 0.0, 5.0, "a"},
+	{4.0, 1.0, 5.0, Missing{}},
+	{10.0, 2.0, 5.0, "b"},
+}
+
+func TestScaler(t *testing.T) {
+	cases := []struct {
+		method   ScaleMethod
+		expected []float64 // Second row, first and second column.
+	}{
+		{ScaleMinMax, []float64{1.0 / 9, 1}},
+		{ScaleRobust, []float64{-0.5, 0.5}},
+		{ScaleMaxAbs, []float64{0.2, 0.5}},
+		{ScaleNone, []float64{2, 2}},
+	}
+	for _, c := range cases {
+		data := cloneTable(scalerData)
+		s := &Scaler{Method: c.method}
+		if err := s.Fit(data); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Transform(data); err != nil {
+			t.Fatal(err)
+		}
+		for j, e := range c.expected {
+			if g := data[1][j].(float64); !floatsAreEqual(g, e) {
+				t.Errorf("%s: column %d expected %v, got %v", c.method, j, e, g)
+			}
+		}
+		// Constant column.
+		if g := data[1][2].(float64); g != 0 && c.method != ScaleNone && c.method != ScaleMaxAbs {
+			t.Errorf("%s: constant column scaled to %v", c.method, g)
+		}
+		if data[1][3] != "b" || !IsMissing(data[3][3]) {
+			t.Errorf("%s: categorical column modified: %v", c.method, data[1][3])
+		}
+	}
+}
+
+func TestScaler_standard(t *testing.T) {
+	data := cloneTable(scalerData)
+	s := &Scaler{Method: ScaleStandard}
+	if err := s.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Transform(data); err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 2; j++ {
+		var stats ColumnStats
+		for _, row := range data {
+			stats.Add(row[j])
+		}
+		if !floatsAreEqual(stats.Mean(), 0) || !floatsAreEqual(stats.StdDev(), 1) {
+			t.Errorf("column %d: expected mean 0 and std 1, got %v and %v", j, stats.Mean(), stats.StdDev())
+		}
+	}
+	// Constant column.
+	for i, row := range data {
+		if row[2] != 0.0 {
+			t.Errorf("row %d: constant column scaled to %v", i, row[2])
+		}
+	}
+}
+
+func TestScaler_perColumn(t *testing.T) {
+	data := cloneTable(scalerData)
+	s := &Scaler{Method: ScaleNone, Columns: map[int]ScaleMethod{1: ScaleMaxAbs}}
+	if err := s.Fit(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Transform(data); err != nil {
+		t.Fatal(err)
+	}
+	if data[0][0] != 1.0 || data[0][1] != -1.0 {
+		t.Errorf("expected [1 -1], got %v", data[0][:2])
+	}
+}
+
+func TestNormalize_zeroVariance(t *testing.T) {
+	data := MemoryTable{{3.0, 1.0}, {3.0, 2.0}}
+	if _, _, _, err := Normalize(data, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data[0][0] != 0.0 || data[1][0] != 0.0 {
+		t.Errorf("constant column must be zeroed: %v", data)
+	}
+}
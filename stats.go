@@ -0,0 +1,215 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"sort"
+)
+
+// ColumnStats accumulates statistics of a Table's column
+// in a single pass, so it can be used with streaming Tables.
+// Mean and variance use Welford's algorithm that is
+// numerically stable. Stats of different shards
+// of a column can be combined with Merge.
+//
+// Memory does not grow with the number of numeric
+// values: their distinct count is estimated with a
+// bounded sketch, only categorical labels are counted.
+//
+// The zero value is ready to use.
+type ColumnStats struct {
+	Count   int     // Non missing values.
+	Missing int     // Missing values.
+	Min     float64 // Minimum of numeric values.
+	Max     float64 // Maximum of numeric values.
+	numbers int
+	mean    float64
+	m2      float64 // Sum of squared deviations from mean.
+	labels  int     // Number of categorical values.
+	counts  map[string]int
+	hashes  []uint64 // Smallest hashes of distinct numeric values, sorted.
+}
+
+// distinctSketch is the number of hashes
+// kept to estimate distinct numeric values.
+const distinctSketch = 1024
+
+// Add accumulates v that can be a float64,
+// a string, a *Category or Missing.
+func (s *ColumnStats) Add(v interface{}) error {
+	if s.counts == nil {
+		s.counts = make(map[string]int)
+	}
+	switch e := v.(type) {
+	case float64:
+		if s.numbers == 0 || e < s.Min {
+			s.Min = e
+		}
+		if s.numbers == 0 || e > s.Max {
+			s.Max = e
+		}
+		s.numbers++
+		delta := e - s.mean
+		s.mean += delta / float64(s.numbers)
+		s.m2 += delta * (e - s.mean)
+		s.addHash(hashFloat(e))
+	case string:
+		s.labels++
+		s.counts[e]++
+	case *Category:
+		s.labels++
+		s.counts[e.label]++
+	case Missing:
+		s.Missing++
+		return nil
+	default:
+		return unknownTypeErr(v)
+	}
+	s.Count++
+	return nil
+}
+
+// Merge combines in s the stats of o,
+// computed on other values of the same column.
+func (s *ColumnStats) Merge(o *ColumnStats) {
+	if o.numbers > 0 {
+		if s.numbers == 0 || o.Min < s.Min {
+			s.Min = o.Min
+		}
+		if s.numbers == 0 || o.Max > s.Max {
+			s.Max = o.Max
+		}
+		n := float64(s.numbers + o.numbers)
+		delta := o.mean - s.mean
+		s.m2 += o.m2 + delta*delta*float64(s.numbers)*float64(o.numbers)/n
+		s.mean += delta * float64(o.numbers) / n
+		s.numbers += o.numbers
+	}
+	if s.counts == nil {
+		s.counts = make(map[string]int, len(o.counts))
+	}
+	for k, c := range o.counts {
+		s.counts[k] += c
+	}
+	for _, h := range o.hashes {
+		s.addHash(h)
+	}
+	s.labels += o.labels
+	s.Count += o.Count
+	s.Missing += o.Missing
+}
+
+// Categorical reports whether the column
+// has categorical values.
+func (s *ColumnStats) Categorical() bool {
+	return s.labels > 0
+}
+
+// Mean returns the mean of numeric values,
+// NaN if there are none.
+func (s *ColumnStats) Mean() float64 {
+	if s.numbers == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+// Variance returns the sample variance
+// of numeric values, NaN if there are
+// less than two of them.
+func (s *ColumnStats) Variance() float64 {
+	if s.numbers < 2 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.numbers-1)
+}
+
+// StdDev returns the sample standard
+// deviation of numeric values.
+func (s *ColumnStats) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Distinct returns the number of distinct values.
+// It is exact for categorical values and for up to
+// 1024 distinct numeric values, estimated beyond.
+func (s *ColumnStats) Distinct() int {
+	n := len(s.hashes)
+	if n == distinctSketch {
+		// K minimum values estimate: k hashes
+		// uniformly spread up to the largest one.
+		n = int(float64(distinctSketch-1)/((float64(s.hashes[n-1])+1)/math.MaxUint64) + 0.5)
+	}
+	return len(s.counts) + n
+}
+
+// addHash keeps h if it is among
+// the smallest distinct hashes.
+func (s *ColumnStats) addHash(h uint64) {
+	n := len(s.hashes)
+	i := sort.Search(n, func(k int) bool { return s.hashes[k] >= h })
+	if i < n && s.hashes[i] == h {
+		return
+	}
+	if n == distinctSketch {
+		if i == n {
+			return
+		}
+		s.hashes = s.hashes[:n-1]
+	}
+	s.hashes = append(s.hashes, 0)
+	copy(s.hashes[i+1:], s.hashes[i:])
+	s.hashes[i] = h
+}
+
+// hashFloat mixes bits of v with
+// the finalizer of splitmix64.
+func hashFloat(v float64) uint64 {
+	if v == 0 {
+		// -0 equals 0.
+		v = 0
+	}
+	h := math.Float64bits(v)
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// Vocabulary returns the sorted labels
+// of categorical values.
+func (s *ColumnStats) Vocabulary() Vocabulary {
+	var v Vocabulary
+	for l := range s.counts {
+		v = append(v, l)
+	}
+	sort.Strings(v)
+	return v
+}
+
+// ComputeStats returns the stats of
+// each of data's columns reading it once.
+func ComputeStats(data Table) ([]*ColumnStats, error) {
+	nRows, nColumns := data.Caps()
+	stats := make([]*ColumnStats, nColumns)
+	for j := range stats {
+		stats[j] = &ColumnStats{}
+	}
+	for i := 0; i < nRows; i++ {
+		row, err := data.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		for j, e := range row {
+			if j >= nColumns {
+				break
+			}
+			if err := stats[j].Add(e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stats, nil
+}
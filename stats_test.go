@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColumnStats(t *testing.T) {
+	var s ColumnStats
+	for _, v := range []interface{}{4.0, 7.0, Missing{}, 13.0, 16.0, 7.0} {
+		if err := s.Add(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Count != 5 || s.Missing != 1 || s.Distinct() != 4 {
+		t.Errorf("wrong counts: %d, %d, %d", s.Count, s.Missing, s.Distinct())
+	}
+	if s.Min != 4 || s.Max != 16 || !floatsAreEqual(s.Mean(), 9.4) {
+		t.Errorf("wrong min, max or mean: %v, %v, %v", s.Min, s.Max, s.Mean())
+	}
+	if !floatsAreEqual(s.Variance(), 24.3) {
+		t.Errorf("expected variance 24.3, got %v", s.Variance())
+	}
+	if err := s.Add(1); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
+
+// Values with a large offset lose precision
+// with the naive sum of squares.
+func TestColumnStats_stable(t *testing.T) {
+	var s ColumnStats
+	for _, v := range []float64{4, 7, 13, 16} {
+		s.Add(1e9 + v)
+	}
+	if math.Abs(s.Variance()-30) > 1e-6 {
+		t.Errorf("expected variance 30, got %v", s.Variance())
+	}
+}
+
+func TestColumnStats_Merge(t *testing.T) {
+	values := []interface{}{"a", 2.0, 3.5, Missing{}, -1.0, "b", 10.0, "a"}
+	var whole, a, b ColumnStats
+	for i, v := range values {
+		whole.Add(v)
+		if i < 3 {
+			a.Add(v)
+		} else {
+			b.Add(v)
+		}
+	}
+	a.Merge(&b)
+	if a.Count != whole.Count || a.Missing != whole.Missing || a.Distinct() != whole.Distinct() {
+		t.Errorf("wrong merged counts: %+v", a)
+	}
+	if a.Min != whole.Min || a.Max != whole.Max || !floatsAreEqual(a.Mean(), whole.Mean()) || !floatsAreEqual(a.Variance(), whole.Variance()) {
+		t.Errorf("merged stats differ: %+v, %+v", a, whole)
+	}
+	if v := a.Vocabulary(); len(v) != 2 || v[0] != "a" || v[1] != "b" {
+		t.Errorf("wrong vocabulary: %v", v)
+	}
+}
+
+func TestColumnStats_Distinct(t *testing.T) {
+	var s, a, b ColumnStats
+	n := 100000
+	for i := 0; i < n; i++ {
+		v := float64(i % (n / 2))
+		s.Add(v)
+		if i%2 == 0 {
+			a.Add(v)
+		} else {
+			b.Add(v)
+		}
+	}
+	if len(s.hashes) > distinctSketch {
+		t.Errorf("sketch grew to %d hashes", len(s.hashes))
+	}
+	if d := s.Distinct(); math.Abs(float64(d-n/2)) > 0.1*float64(n/2) {
+		t.Errorf("expected about %d distinct values, got %d", n/2, d)
+	}
+	a.Merge(&b)
+	if a.Distinct() != s.Distinct() {
+		t.Errorf("merged distinct %d, expected %d", a.Distinct(), s.Distinct())
+	}
+}
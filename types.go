@@ -5,6 +5,8 @@
 package learn
 
 import (
+	"bytes"
+	"encoding/gob"
 	"errors"
 	"fmt"
 )
@@ -45,6 +47,69 @@ type Table interface {
 	Update(i int, r []interface{}) error // Substitutes i-th row with r.
 }
 
+// Missing is stored in Table's rows
+// in place of missing values.
+type Missing struct{}
+
+func (Missing) String() string {
+	return "NA"
+}
+
+// IsMissing reports whether v is a missing value.
+func IsMissing(v interface{}) bool {
+	_, ok := v.(Missing)
+	return ok
+}
+
+// ColumnType identifies the type of values
+// stored in a Table's column.
+type ColumnType uint8
+
+const (
+	AutoColumn        ColumnType = iota // Type is inferred from data.
+	NumericColumn                       // Values are float64.
+	CategoricalColumn                   // Values are strings, categories once normalized.
+)
+
+func (c ColumnType) String() string {
+	switch c {
+	case NumericColumn:
+		return "numeric"
+	case CategoricalColumn:
+		return "categorical"
+	default:
+		return "auto"
+	}
+}
+
+// Column describes a Table's column.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Schema describes columns of a Table.
+type Schema struct {
+	Columns []Column
+	Label   int // Index of label column, -1 if there is no label.
+}
+
+// Names returns columns' names.
+func (s Schema) Names() []string {
+	names := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// SchemaTable is a Table
+// that knows its Schema.
+type SchemaTable interface {
+	Table
+	Schema() Schema
+}
+
 // MemoryTable is a Table that stores data in memory.
 type MemoryTable [][]interface{}
 
@@ -78,60 +143,164 @@ func (t MemoryTable) Update(i int, r []interface{}) error {
 	return nil
 }
 
+// schemaTable is a MemoryTable
+// that exposes its Schema.
+type schemaTable struct {
+	MemoryTable
+	schema Schema
+}
+
+// Caps implements Table's Caps.
+func (t *schemaTable) Caps() (int, int) {
+	return len(t.MemoryTable), len(t.schema.Columns)
+}
+
+// Schema implements SchemaTable's Schema.
+func (t *schemaTable) Schema() Schema {
+	return t.schema
+}
+
+// Vocabulary is the ordered set of labels
+// a categorical feature can assume.
+type Vocabulary []string
+
+// NewVocabulary returns the Vocabulary
+// of labels sorted and without duplicates.
+func NewVocabulary(labels ...string) Vocabulary {
+	set := make(map[string]struct{}, len(labels))
+	for _, l := range labels {
+		set[l] = struct{}{}
+	}
+	return Vocabulary(orderMapSet(set))
+}
+
+// Index returns the position of label in v,
+// -1 if it is not present.
+func (v Vocabulary) Index(label string) int {
+	for i, e := range v {
+		if e == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// Category models a categorical feature.
+// It stores the label and its index in the Vocabulary
+// of the column, so with Vocabulary{"bar", "foo", "zoo"}:
 //
-// Unexposed
+//	"bar" -> 0
+//	"foo" -> 1
+//	"zoo" -> 2
 //
-
-// category models a categorical feature.
-// Strings are mapped to a binary representation
-// like:
-// "foo" ->	"[0,0,1]"
-// "bar" ->	"[0,1,0]"
-// "zoo" ->	"[1,0,0]"
-type category struct {
+// Tables implemented outside this package
+// must use Categories to store categorical values
+// that models can work with.
+type Category struct {
 	data      uint
 	catNumber uint
 	label     string
 }
 
-func newCategory(k string, orderedSet []string) *category {
-	l := len(orderedSet)
-	var j uint
-	for i, e := range orderedSet {
-		if e == k {
-			j = uint(i)
-			break
-		}
+// NewCategory returns the Category of label
+// in vocabulary v. Labels not in v are unseen
+// and get index -1.
+func NewCategory(label string, v Vocabulary) *Category {
+	// Unseen labels are stored with
+	// data out of vocabulary's range.
+	j := uint(len(v))
+	if i := v.Index(label); i >= 0 {
+		j = uint(i)
 	}
-	return &category{
+	return &Category{
 		data:      j,
-		catNumber: uint(l),
-		label:     k,
+		catNumber: uint(len(v)),
+		label:     label,
+	}
+}
+
+// Label returns the label of c.
+func (c *Category) Label() string {
+	return c.label
+}
+
+// Index returns the position of c's label
+// in the Vocabulary used to create it,
+// -1 if the label was not in it.
+func (c *Category) Index() int {
+	if c.unseen() {
+		return -1
+	}
+	return int(c.data)
+}
+
+func (c *Category) unseen() bool {
+	return c.data >= c.catNumber
+}
+
+// Equal reports whether c and b have the same
+// label and come from Vocabularies of the same size.
+func (c *Category) Equal(b *Category) bool {
+	if c == nil || b == nil {
+		return c == b
 	}
+	return c.label == b.label && c.data == b.data && c.catNumber == b.catNumber
 }
 
-func (c *category) add(b *category) {
+//
+// Unexposed
+//
+
+func (c *Category) add(b *Category) {
 	c.data += b.data
 }
 
-func (c *category) zero() {
+func (c *Category) zero() {
 	c.data = 0
 }
 
 // mean calculates mean for an element of
 // a centroid previously incremented l times.
 // TODO test for overflow, if 0b0000 & 0b111110000 != 0
-func (c *category) mean(l int) {
+func (c *Category) mean(l int) {
 	c.data = c.data / uint(l)
 }
 
 // distance returns simple matching distance
-// from the passed Category.
-// Returning value is ∈ [0,1].
-func (c *category) distance(b *category) float64 {
-	return float64(hammingD(c.data, b.data)) / float64(c.catNumber)
-}
-func (c *category) String() string {
-	format := fmt.Sprintf("%%s-%%0%db", c.catNumber)
-	return fmt.Sprintf(format, c.label, c.data)
+// from the passed Category: 0 for the same
+// label, 1 otherwise.
+func (c *Category) distance(b *Category) float64 {
+	if c.label == b.label {
+		return 0
+	}
+	return 1
+}
+
+func (c *Category) String() string {
+	return fmt.Sprintf("%s-%d", c.label, c.data)
+}
+
+// gobCategory is used to serialize
+// unexported fields of Category.
+type gobCategory struct {
+	Data      uint
+	CatNumber uint
+	Label     string
+}
+
+// GobEncode implements gob.GobEncoder.
+func (c *Category) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(gobCategory{c.data, c.catNumber, c.label})
+	return buf.Bytes(), err
+}
+
+// GobDecode implements gob.GobDecoder.
+func (c *Category) GobDecode(b []byte) error {
+	var g gobCategory
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&g); err != nil {
+		return err
+	}
+	c.data, c.catNumber, c.label = g.Data, g.CatNumber, g.Label
+	return nil
 }
@@ -0,0 +1,43 @@
+// Copyright (c) 2017 Andrea Masi. All rights reserved.
+// Use of this source code is governed by MIT license
+// which that can be found in the LICENSE.txt file.
+
+package learn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewVocabulary(t *testing.T) {
+	v := NewVocabulary("zoo", "foo", "bar", "foo")
+	if want := (Vocabulary{"bar", "foo", "zoo"}); !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %v, want %v", v, want)
+	}
+	if i := v.Index("zoo"); i != 2 {
+		t.Errorf("Index(zoo) = %d, want 2", i)
+	}
+	if i := v.Index("moo"); i != -1 {
+		t.Errorf("Index(moo) = %d, want -1", i)
+	}
+}
+
+func TestCategory_accessors(t *testing.T) {
+	v := NewVocabulary("foo", "bar", "zoo")
+	c := NewCategory("foo", v)
+	if c.Label() != "foo" || c.Index() != 1 {
+		t.Fatalf("got %q at %d, want foo at 1", c.Label(), c.Index())
+	}
+	if !c.Equal(NewCategory("foo", v)) {
+		t.Error("same label and vocabulary must be equal")
+	}
+	if c.Equal(NewCategory("bar", v)) {
+		t.Error("different labels must not be equal")
+	}
+	if c.Equal(NewCategory("foo", NewVocabulary("foo", "bar"))) {
+		t.Error("different vocabularies must not be equal")
+	}
+	if c.Equal(nil) {
+		t.Error("Category must not be equal to nil")
+	}
+}
@@ -7,6 +7,7 @@ package learn
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sort"
 )
 
@@ -102,7 +103,7 @@ func ConfusionM(expect, predict Table) (ConfMatrix, error) {
 		if err != nil {
 			return ConfMatrix{}, err
 		}
-		expectedLabel, ok := row[len(row)-1].(*category)
+		expectedLabel, ok := row[len(row)-1].(*Category)
 		if !ok {
 			return ConfMatrix{}, fmt.Errorf("learn: %v is not a category", row[len(row)-1])
 		}
@@ -177,3 +178,225 @@ func computeRecall(k string, cm ConfMatrix) float64 {
 	return tp / (tp + totalFn)
 
 }
+
+// RegressionReport stores error metrics
+// computed on the residuals of a Regression.
+type RegressionReport struct {
+	MAE       float64   // Mean absolute error.
+	MSE       float64   // Mean squared error.
+	RMSE      float64   // Root mean squared error.
+	R2        float64   // Coefficient of determination, 1 or 0 for constant observed values.
+	MAPE      float64   // Mean absolute percentage error.
+	MedianAE  float64   // Median absolute error.
+	Residuals Quantiles // Quantiles of observed - predicted values.
+}
+
+// Quantiles stores the five number summary
+// of a distribution.
+type Quantiles struct {
+	Min, Q1, Median, Q3, Max float64
+}
+
+func (q Quantiles) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%12s | %12s | %12s | %12s | %12s |\n", "min", "1Q", "median", "3Q", "max")
+	fmt.Fprintf(&buf, "%12.4f | %12.4f | %12.4f | %12.4f | %12.4f |", q.Min, q.Q1, q.Median, q.Q3, q.Max)
+	return buf.String()
+}
+
+func (r RegressionReport) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%12s | %-12s |\n", "metric", "value")
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "MAE", r.MAE)
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "MSE", r.MSE)
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "RMSE", r.RMSE)
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "R2", r.R2)
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "MAPE", r.MAPE)
+	fmt.Fprintf(&buf, "%12s | %12.4f |\n", "median AE", r.MedianAE)
+	fmt.Fprintf(&buf, "Residuals:\n%v", r.Residuals)
+	return buf.String()
+}
+
+// ValidateRegression computes error metrics for
+// values predicted by a Regression.
+// Observed values are taken from the last field
+// of expect's rows, as in NewLinearRegression training data.
+//
+// MAPE is computed over samples with
+// a non zero observed value only.
+func ValidateRegression(expect Table, predict []float64) (RegressionReport, error) {
+	observed, err := observedValues(expect, predict)
+	if err != nil {
+		return RegressionReport{}, err
+	}
+	return regressionReport(observed, predict, nil), nil
+}
+
+// ValidateWeightedRegression is like ValidateRegression
+// but every metric is weighted by sample weights,
+// as minimized by NewWeightedLinearRegression.
+// Residuals' quantiles and median absolute error
+// are weighted quantiles.
+//
+// Unlike NewWeightedLinearRegression, weights
+// must not be nil.
+func ValidateWeightedRegression(expect Table, predict, weights []float64) (RegressionReport, error) {
+	observed, err := observedValues(expect, predict)
+	if err != nil {
+		return RegressionReport{}, err
+	}
+	if err := checkWeights(weights, len(observed)); err != nil {
+		return RegressionReport{}, err
+	}
+	return regressionReport(observed, predict, weights), nil
+}
+
+// observedValues returns values of dependent variable
+// stored in the last field of expect's rows.
+func observedValues(expect Table, predict []float64) ([]float64, error) {
+	nRows, _ := expect.Caps()
+	if nRows == 0 {
+		return nil, ErrNoData
+	}
+	if nRows != len(predict) {
+		return nil, fmt.Errorf("learn: %d observed values but %d predictions", nRows, len(predict))
+	}
+	observed := make([]float64, nRows)
+	for i := 0; i < nRows; i++ {
+		row, err := expect.Row(i)
+		if err != nil {
+			return nil, err
+		}
+		y, ok := row[len(row)-1].(float64)
+		if !ok {
+			return nil, unknownTypeErr(row[len(row)-1])
+		}
+		observed[i] = y
+	}
+	return observed, nil
+}
+
+// regressionReport computes metrics weighting
+// samples by weights, all samples have
+// the same weight if it is nil.
+func regressionReport(observed, predict, weights []float64) RegressionReport {
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+	var mean, total float64
+	for i, y := range observed {
+		mean += weight(i) * y
+		total += weight(i)
+	}
+	mean /= total
+	var r RegressionReport
+	var ssTot, apeSum, apeTotal float64
+	residuals := make([]float64, len(observed))
+	absErrors := make([]float64, len(observed))
+	for i, y := range observed {
+		w := weight(i)
+		e := y - predict[i]
+		residuals[i] = e
+		absErrors[i] = math.Abs(e)
+		r.MAE += w * absErrors[i]
+		r.MSE += w * e * e
+		ssTot += w * (y - mean) * (y - mean)
+		if y != 0 {
+			apeSum += w * math.Abs(e/y)
+			apeTotal += w
+		}
+	}
+	switch {
+	case ssTot != 0:
+		r.R2 = 1 - r.MSE/ssTot
+	case r.MSE == 0:
+		// Constant observed values
+		// perfectly predicted.
+		r.R2 = 1
+	default:
+		// Constant observed values, the model
+		// is not better than their mean.
+		r.R2 = 0
+	}
+	r.MAE /= total
+	r.MSE /= total
+	r.RMSE = math.Sqrt(r.MSE)
+	r.MAPE = math.NaN()
+	if apeTotal > 0 {
+		r.MAPE = 100 * apeSum / apeTotal
+	}
+	if weights == nil {
+		sort.Float64s(absErrors)
+		r.MedianAE = quantile(absErrors, 0.5)
+		sort.Float64s(residuals)
+		r.Residuals = fiveNumbers(residuals)
+		return r
+	}
+	r.MedianAE = weightedQuantile(absErrors, weights, 0.5)
+	r.Residuals = Quantiles{
+		Min:    weightedQuantile(residuals, weights, 0),
+		Q1:     weightedQuantile(residuals, weights, 0.25),
+		Median: weightedQuantile(residuals, weights, 0.5),
+		Q3:     weightedQuantile(residuals, weights, 0.75),
+		Max:    weightedQuantile(residuals, weights, 1),
+	}
+	return r
+}
+
+// fiveNumbers returns the five number summary
+// of sorted values.
+func fiveNumbers(sorted []float64) Quantiles {
+	return Quantiles{
+		Min:    sorted[0],
+		Q1:     quantile(sorted, 0.25),
+		Median: quantile(sorted, 0.5),
+		Q3:     quantile(sorted, 0.75),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// quantile returns the p-th quantile
+// of sorted values interpolating linearly
+// between closest ranks.
+func quantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	h := p * float64(len(sorted)-1)
+	lo := math.Floor(h)
+	i := int(lo)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (h-lo)*(sorted[i+1]-sorted[i])
+}
+
+// weightedQuantile returns the smallest value
+// for which cumulative weight reaches
+// the p fraction of total weight.
+// Samples with zero weight are ignored.
+func weightedQuantile(values, weights []float64, p float64) float64 {
+	idx := make([]int, 0, len(values))
+	var total float64
+	for i, w := range weights {
+		if w > 0 {
+			idx = append(idx, i)
+			total += w
+		}
+	}
+	if len(idx) == 0 {
+		return math.NaN()
+	}
+	sort.Slice(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+	var cum float64
+	for _, i := range idx {
+		cum += weights[i]
+		if cum >= p*total {
+			return values[i]
+		}
+	}
+	return values[idx[len(idx)-1]]
+}
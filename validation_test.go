@@ -76,3 +76,96 @@ func TestComputeRecall(t *testing.T) {
 		t.Fatal("expected a recall of 0, got:", r)
 	}
 }
+
+func TestValidateRegression(t *testing.T) {
+	var expect MemoryTable = [][]interface{}{
+		{1.0, 2.0},
+		{2.0, 4.0},
+		{3.0, 6.0},
+		{4.0, 8.0},
+	}
+	predict := []float64{3, 4, 5, 8}
+	r, err := ValidateRegression(expect, predict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{"MAE", r.MAE, 0.5},
+		{"MSE", r.MSE, 0.5},
+		{"RMSE", r.RMSE, math.Sqrt(0.5)},
+		{"R2", r.R2, 0.9},
+		{"MAPE", r.MAPE, 100 * (0.5 + 1.0/6) / 4},
+		{"MedianAE", r.MedianAE, 0.5},
+		{"residuals min", r.Residuals.Min, -1},
+		{"residuals median", r.Residuals.Median, 0},
+		{"residuals max", r.Residuals.Max, 1},
+	}
+	for _, c := range cases {
+		if !floatsAreEqual(c.got, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+	_, err = ValidateRegression(expect, predict[:2])
+	if err == nil {
+		t.Fatal("expected an error for mismatched lengths")
+	}
+}
+
+func TestValidateRegression_constant(t *testing.T) {
+	var expect MemoryTable = [][]interface{}{
+		{1.0, 5.0},
+		{2.0, 5.0},
+		{3.0, 5.0},
+	}
+	r, err := ValidateRegression(expect, []float64{5, 5, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.R2 != 1 {
+		t.Errorf("perfect fit of constant values: expected R2 1, got %v", r.R2)
+	}
+	r, err = ValidateRegression(expect, []float64{4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.R2 != 0 {
+		t.Errorf("constant values: expected R2 0, got %v", r.R2)
+	}
+}
+
+func TestValidateWeightedRegression(t *testing.T) {
+	var expect MemoryTable = [][]interface{}{
+		{1.0, 2.0},
+		{2.0, 4.0},
+		{3.0, 6.0},
+		{4.0, 8.0},
+	}
+	predict := []float64{3, 4, 5, 8}
+	r, err := ValidateWeightedRegression(expect, predict, []float64{1, 1, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := ValidateRegression(expect, predict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floatsAreEqual(r.MSE, u.MSE) || !floatsAreEqual(r.R2, u.R2) || !floatsAreEqual(r.MAPE, u.MAPE) {
+		t.Errorf("unit weights must match unweighted report, expected:\n%v\ngot:\n%v", u, r)
+	}
+	// Only exactly predicted samples count.
+	r, err = ValidateWeightedRegression(expect, predict, []float64{0, 1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.MAE != 0 || r.MSE != 0 || r.R2 != 1 || r.Residuals.Max != 0 {
+		t.Errorf("expected a perfect fit, got:\n%v", r)
+	}
+	for _, w := range [][]float64{{0, 0, 0, 0}, {1, -1, 1, 1}, {1, 1, math.NaN(), 1}} {
+		if _, err := ValidateWeightedRegression(expect, predict, w); err == nil {
+			t.Errorf("expected an error for weights %v", w)
+		}
+	}
+}